@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+)
+
+// asyncRequest is an initiator request (B2C, B2B, tax remittance, ...) that M-Pesa
+// acknowledges at once and whose outcome is delivered to result_url.
+type asyncRequest struct {
+	// name names the request in messages, e.g. "B2C payment"
+	name string
+
+	// summary describes the request when confirming it in production
+	summary string
+
+	// progress is shown next to the spinner while the request is sent
+	progress string
+
+	// send makes the request with an authenticated client
+	send func(client *mpesa.Client) (*mpesa.AsyncResponse, error)
+}
+
+// runAsyncRequest loads the active profile, asks for confirmation in production,
+// sends req and prints the acknowledgement.
+func runAsyncRequest(req asyncRequest) error {
+	config, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	if err := confirmProduction(config, req.summary); err != nil {
+		return err
+	}
+
+	done := make(chan bool)
+	go showSpinner(req.progress, done)
+
+	client, err := configClient(config)
+	if err != nil {
+		done <- true
+		<-done
+		fmt.Println("\n❌ Authentication failed.")
+		return err
+	}
+
+	result, err := req.send(client)
+	done <- true
+	<-done
+
+	if err != nil {
+		fmt.Printf("\n❌ %s failed.\n", capitalize(req.name))
+		return fmt.Errorf("error sending %s: %w", req.name, err)
+	}
+
+	fmt.Printf("\n✔ %s accepted!\n", capitalize(req.name))
+	fmt.Println("--------------------")
+	fmt.Printf("Response Code: %s\n", result.ResponseCode)
+	fmt.Printf("Description: %s\n", result.ResponseDescription)
+	fmt.Printf("Conversation ID: %s\n", result.ConversationID)
+	fmt.Printf("Originator Conversation ID: %s\n", result.OriginatorConversationID)
+	fmt.Println("--------------------")
+	fmt.Printf("💡 Tip: The result will be sent to %s.\n", config.ResultURL)
+
+	return nil
+}
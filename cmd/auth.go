@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// authCmd represents the auth parent command
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Inspect and rotate the stored credentials",
+	Long: `Parent command for managing the consumer key and secret stored by 'mpesa-cli login'.
+
+Use 'auth status' to see which credentials a profile has and when they were last
+verified, and 'auth rotate' to replace the consumer secret without downtime.`,
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+}
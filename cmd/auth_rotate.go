@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	rotateConsumerKey string
+	rotateEnvironment string
+)
+
+var authRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Replace the consumer secret after verifying the new one",
+	Long: `Prompts for a new consumer secret, checks it by requesting an access token, and only
+then replaces the stored secret. If M-Pesa rejects the new secret, the current
+credentials are left in place.
+
+Regenerate the secret on the Daraja portal first. If the app was given a new consumer
+key as well, pass it with --consumer-key. The new secret is read from stdin when it
+is not a terminal.`,
+	Example: `  mpesa-cli auth rotate
+  mpesa-cli auth rotate --consumer-key NEW_KEY --profile production`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile := activeProfile()
+
+		oldKey, _, err := mpesa.GetProfileCredentials(profile)
+		if err != nil {
+			return err
+		}
+
+		environment, err := targetEnvironment(rotateEnvironment)
+		if err != nil {
+			return err
+		}
+
+		consumerKey := strings.TrimSpace(rotateConsumerKey)
+		if consumerKey == "" {
+			consumerKey = oldKey
+		}
+
+		consumerSecret, err := readSecret("? New Consumer Secret: ")
+		if err != nil {
+			return fmt.Errorf("failed to read secret: %w", err)
+		}
+		consumerSecret = strings.TrimSpace(consumerSecret)
+		if consumerSecret == "" {
+			fmt.Println("❌ Consumer Secret cannot be empty")
+			return fmt.Errorf("consumer secret cannot be empty")
+		}
+
+		done := make(chan bool)
+		go showSpinner(fmt.Sprintf("Verifying the new credentials with M-Pesa (%s)...", environment), done)
+
+		client := mpesa.NewClient(&mpesa.Config{Environment: environment}, mpesa.WithUserAgent(userAgent()))
+		_, err = client.GetAccessToken(consumerKey, consumerSecret)
+		done <- true
+		<-done
+
+		if err != nil {
+			fmt.Println("\n❌ Verification failed; the current credentials have been kept.")
+			return fmt.Errorf("new credentials were rejected by %s: %w", environment, err)
+		}
+
+		if err := mpesa.RotateProfileCredentials(profile, consumerKey, consumerSecret); err != nil {
+			fmt.Println("\n❌ Rotation failed; the current credentials have been kept.")
+			return fmt.Errorf("failed to store the new credentials: %w", err)
+		}
+
+		// Tokens issued for the old secret may be revoked with it
+		_ = forgetCachedTokens(oldKey)
+		if consumerKey != oldKey {
+			_ = forgetCachedTokens(consumerKey)
+		}
+		recordVerification(profile, environment)
+
+		fmt.Printf("\n✅ Credentials of profile '%s' rotated (consumer key: %s).\n", profile, maskSecret(consumerKey))
+		return nil
+	},
+}
+
+func init() {
+	authCmd.AddCommand(authRotateCmd)
+	authRotateCmd.Flags().StringVar(&rotateConsumerKey, "consumer-key", "", "New consumer key (default is to keep the current one)")
+	authRotateCmd.Flags().StringVar(&rotateEnvironment, "env", "", "Environment to verify the new secret against: sandbox or production (default is the profile's environment)")
+}
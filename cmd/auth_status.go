@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+
+	"github.com/spf13/cobra"
+)
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the stored credentials of a profile",
+	Long: `Shows which credentials are stored for the selected profile (see --profile), the
+masked consumer key, when the credentials were last verified against M-Pesa and when
+the cached access tokens expire. No request is made to M-Pesa.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile := activeProfile()
+
+		status, err := mpesa.GetProfileCredentialStatus(profile)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("👤 Profile: %s\n", profile)
+		fmt.Printf("🔐 Credential backend: %s\n", credentialBackendName())
+		fmt.Println("--------------------")
+		fmt.Printf("Consumer Key: %s\n", valueOrNone(maskSecret(status.ConsumerKey)))
+		fmt.Printf("Consumer Secret: %s\n", storedOrNot(status.ConsumerSecret))
+		fmt.Printf("Passkey: %s\n", storedOrNot(status.Passkey))
+		fmt.Printf("Initiator Password: %s\n", storedOrNot(status.InitiatorPassword))
+		fmt.Printf("Last Verified: %s\n", lastVerified(profile))
+		if status.ConsumerKey != "" {
+			for _, line := range tokenExpiries(status.ConsumerKey) {
+				fmt.Println(line)
+			}
+		}
+		fmt.Println("--------------------")
+
+		if status.ConsumerKey == "" || !status.ConsumerSecret {
+			fmt.Println("💡 Tip: Run `mpesa-cli login` to store your credentials.")
+		}
+
+		return nil
+	},
+}
+
+// storedOrNot describes whether a credential is stored.
+func storedOrNot(stored bool) string {
+	if stored {
+		return "stored"
+	}
+	return "(not stored)"
+}
+
+// lastVerified describes when the credentials of profile were last verified.
+func lastVerified(profile string) string {
+	path, err := mpesa.DefaultVerificationsPath()
+	if err != nil {
+		return "unknown"
+	}
+
+	verification, err := mpesa.NewVerificationStore(path).Get(profile)
+	if err != nil {
+		return fmt.Sprintf("unknown (%v)", err)
+	}
+	if verification == nil {
+		return "never"
+	}
+
+	return fmt.Sprintf("%s (%s)", verification.VerifiedAt.Local().Format("2006-01-02 15:04:05"), verification.Environment)
+}
+
+// tokenExpiries describes the access tokens cached for consumerKey: one line for each
+// environment, followed by any custom base URLs tokens were cached for.
+func tokenExpiries(consumerKey string) []string {
+	path, err := mpesa.DefaultTokenCachePath()
+	if err != nil {
+		return []string{"Access Token: unknown"}
+	}
+	expiries := mpesa.NewTokenCache(path).Expiries(consumerKey)
+
+	var others []string
+	for environment := range expiries {
+		if environment != "sandbox" && environment != "production" {
+			others = append(others, environment)
+		}
+	}
+	sort.Strings(others)
+
+	var lines []string
+	for _, environment := range append([]string{"sandbox", "production"}, others...) {
+		description := "none cached"
+		if expiresAt, ok := expiries[environment]; ok {
+			description = fmt.Sprintf("expires %s (in %s)", expiresAt.Local().Format("2006-01-02 15:04:05"), time.Until(expiresAt).Round(time.Second))
+		}
+		lines = append(lines, fmt.Sprintf("Access Token (%s): %s", environment, description))
+	}
+	return lines
+}
+
+func init() {
+	authCmd.AddCommand(authStatusCmd)
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+)
+
+// TestAuthCommandStructure tests that the auth subcommands are registered
+func TestAuthCommandStructure(t *testing.T) {
+	for _, name := range []string{"rotate", "status"} {
+		if cmd, _, err := authCmd.Find([]string{name}); err != nil || cmd.Name() != name {
+			t.Errorf("expected auth %s command, got %v", name, err)
+		}
+	}
+}
+
+// TestLogoutRemovesCredentialsAndTokens tests that logout erases the credentials, cached tokens and verification
+func TestLogoutRemovesCredentialsAndTokens(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	mpesa.SetCredentialStore(mpesa.NewMemoryStore())
+	defer mpesa.SetCredentialStore(nil)
+
+	profile := activeProfile()
+	if err := mpesa.SetProfileCredentials(profile, "key", "secret"); err != nil {
+		t.Fatalf("failed to set credentials: %v", err)
+	}
+	recordVerification(profile, "sandbox")
+
+	cachePath, err := mpesa.DefaultTokenCachePath()
+	if err != nil {
+		t.Fatalf("failed to get token cache path: %v", err)
+	}
+	cacheKey := mpesa.TokenCacheKey("sandbox", "key")
+	if err := mpesa.NewTokenCache(cachePath).Put(cacheKey, "token", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("failed to cache token: %v", err)
+	}
+
+	if err := logoutCmd.RunE(logoutCmd, nil); err != nil {
+		t.Fatalf("logout failed: %v", err)
+	}
+
+	if _, _, err := mpesa.GetProfileCredentials(profile); err == nil {
+		t.Error("expected credentials to be removed")
+	}
+	if _, _, ok := mpesa.NewTokenCache(cachePath).Get(cacheKey); ok {
+		t.Error("expected cached token to be removed")
+	}
+	if got := lastVerified(profile); got != "never" {
+		t.Errorf("expected verification to be removed, got %s", got)
+	}
+}
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// b2bCmd represents the b2b parent command
+var b2bCmd = &cobra.Command{
+	Use:   "b2b",
+	Short: "Business to Business (B2B) payments",
+	Long: `Parent command for payments from the business shortcode of the selected profile to other businesses.
+
+B2B requests are made by the initiator configured in the profile (initiator and
+security_credential) and their results are delivered to result_url.`,
+}
+
+func init() {
+	rootCmd.AddCommand(b2bCmd)
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+var b2bPayOptions mpesa.B2BOptions
+
+var b2bPayCmd = &cobra.Command{
+	Use:   "pay",
+	Short: "Pay a Paybill or Till from the business shortcode",
+	Long: `Send a B2B payment to another business. Paybills are paid with BusinessPayBill and
+need --account-ref; Tills (--receiver-type till) are paid with BusinessBuyGoods. Use
+--command for the other B2B command IDs, e.g. BusinessToBusinessTransfer.
+The request is acknowledged immediately; the outcome is delivered to result_url.
+
+In production you are asked to confirm the payment unless --yes is given.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAsyncRequest(asyncRequest{
+			name:     "B2B payment",
+			summary:  fmt.Sprintf("pay KES %d to %s %s", b2bPayOptions.Amount, b2bPayOptions.ReceiverType, b2bPayOptions.Receiver),
+			progress: fmt.Sprintf("Sending KES %d to %s", b2bPayOptions.Amount, b2bPayOptions.Receiver),
+			send: func(client *mpesa.Client) (*mpesa.AsyncResponse, error) {
+				return client.B2BPayment(b2bPayOptions)
+			},
+		})
+	},
+}
+
+func init() {
+	b2bCmd.AddCommand(b2bPayCmd)
+	b2bPayCmd.Flags().StringVar(&b2bPayOptions.Receiver, "to", "", "Paybill or Till number receiving the funds (required)")
+	b2bPayCmd.Flags().StringVar(&b2bPayOptions.ReceiverType, "receiver-type", mpesa.ReceiverPaybill, "Receiver type: paybill or till")
+	b2bPayCmd.Flags().IntVar(&b2bPayOptions.Amount, "amount", 0, "Amount to pay in KES (required)")
+	b2bPayCmd.Flags().StringVar(&b2bPayOptions.AccountReference, "account-ref", "", "Account number at the receiving Paybill, up to 13 characters")
+	b2bPayCmd.Flags().StringVar(&b2bPayOptions.CommandID, "command", "", "B2B command ID (default BusinessPayBill or BusinessBuyGoods by receiver type)")
+	b2bPayCmd.Flags().StringVar(&b2bPayOptions.Remarks, "remarks", "B2B Payment", "Remarks sent with the payment, 2 to 100 characters")
+	addYesFlag(b2bPayCmd)
+	_ = b2bPayCmd.MarkFlagRequired("to")
+	_ = b2bPayCmd.MarkFlagRequired("amount")
+}
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// b2cCmd represents the b2c parent command
+var b2cCmd = &cobra.Command{
+	Use:   "b2c",
+	Short: "Business to Customer (B2C) payments",
+	Long: `Parent command for payments from the business shortcode of the selected profile to customers.
+
+B2C requests are made by the initiator configured in the profile (initiator and
+security_credential) and their results are delivered to result_url.`,
+}
+
+func init() {
+	rootCmd.AddCommand(b2cCmd)
+}
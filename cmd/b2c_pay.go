@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+var b2cPayOptions mpesa.B2COptions
+
+var b2cPayCmd = &cobra.Command{
+	Use:   "pay",
+	Short: "Pay a customer from the business shortcode",
+	Long: `Send a B2C payment such as a salary, refund or promotion to a customer's phone.
+The request is acknowledged immediately; the outcome is delivered to result_url.
+
+In production you are asked to confirm the payment unless --yes is given.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAsyncRequest(asyncRequest{
+			name:     "B2C payment",
+			summary:  fmt.Sprintf("pay KES %d to %s as %s", b2cPayOptions.Amount, b2cPayOptions.PhoneNumber, b2cPayOptions.CommandID),
+			progress: fmt.Sprintf("Sending KES %d to %s", b2cPayOptions.Amount, b2cPayOptions.PhoneNumber),
+			send: func(client *mpesa.Client) (*mpesa.AsyncResponse, error) {
+				return client.B2CPayment(b2cPayOptions)
+			},
+		})
+	},
+}
+
+func init() {
+	b2cCmd.AddCommand(b2cPayCmd)
+	b2cPayCmd.Flags().StringVar(&b2cPayOptions.PhoneNumber, "phone", "", "Customer phone number, e.g. 0712345678 (required)")
+	b2cPayCmd.Flags().IntVar(&b2cPayOptions.Amount, "amount", 0, "Amount to pay in KES (required)")
+	b2cPayCmd.Flags().StringVar(&b2cPayOptions.CommandID, "command", mpesa.BusinessPayment, "Payment type: SalaryPayment, BusinessPayment or PromotionPayment")
+	b2cPayCmd.Flags().StringVar(&b2cPayOptions.Remarks, "remarks", "B2C Payment", "Remarks sent with the payment, 2 to 100 characters")
+	b2cPayCmd.Flags().StringVar(&b2cPayOptions.Occasion, "occasion", "", "Optional occasion sent with the payment")
+	addYesFlag(b2cPayCmd)
+	_ = b2cPayCmd.MarkFlagRequired("phone")
+	_ = b2cPayCmd.MarkFlagRequired("amount")
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+var b2cPochiOptions mpesa.B2COptions
+
+var b2cPochiCmd = &cobra.Command{
+	Use:   "pochi",
+	Short: "Pay into a customer's Pochi la Biashara account",
+	Long: `Send a payment from the business shortcode to a Pochi la Biashara account (BusinessPayToPochi).
+The request is acknowledged immediately; the outcome is delivered to result_url.
+
+In production you are asked to confirm the payment unless --yes is given.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAsyncRequest(asyncRequest{
+			name:     "Pochi payment",
+			summary:  fmt.Sprintf("pay KES %d to the Pochi of %s", b2cPochiOptions.Amount, b2cPochiOptions.PhoneNumber),
+			progress: fmt.Sprintf("Sending KES %d to %s", b2cPochiOptions.Amount, b2cPochiOptions.PhoneNumber),
+			send: func(client *mpesa.Client) (*mpesa.AsyncResponse, error) {
+				return client.B2PochiPayment(b2cPochiOptions)
+			},
+		})
+	},
+}
+
+func init() {
+	b2cCmd.AddCommand(b2cPochiCmd)
+	b2cPochiCmd.Flags().StringVar(&b2cPochiOptions.PhoneNumber, "phone", "", "Phone number of the Pochi account, e.g. 0712345678 (required)")
+	b2cPochiCmd.Flags().IntVar(&b2cPochiOptions.Amount, "amount", 0, "Amount to pay in KES (required)")
+	b2cPochiCmd.Flags().StringVar(&b2cPochiOptions.Remarks, "remarks", "Pochi Payment", "Remarks sent with the payment, 2 to 100 characters")
+	b2cPochiCmd.Flags().StringVar(&b2cPochiOptions.Occasion, "occasion", "", "Optional occasion sent with the payment")
+	addYesFlag(b2cPochiCmd)
+	_ = b2cPochiCmd.MarkFlagRequired("phone")
+	_ = b2cPochiCmd.MarkFlagRequired("amount")
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+var b2cTopUpOptions mpesa.B2BOptions
+
+var b2cTopUpCmd = &cobra.Command{
+	Use:   "topup",
+	Short: "Load a B2C utility account from the working account",
+	Long: `Move funds from the working account of the business shortcode to the B2C utility
+account of the shortcode given with --to (BusinessPayToBulk), so that it can make
+B2C payments. The request is acknowledged immediately; the outcome is delivered to result_url.
+
+In production you are asked to confirm the top-up unless --yes is given.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAsyncRequest(asyncRequest{
+			name:     "top-up",
+			summary:  fmt.Sprintf("load KES %d into the B2C account of %s", b2cTopUpOptions.Amount, b2cTopUpOptions.Receiver),
+			progress: fmt.Sprintf("Loading KES %d into %s", b2cTopUpOptions.Amount, b2cTopUpOptions.Receiver),
+			send: func(client *mpesa.Client) (*mpesa.AsyncResponse, error) {
+				return client.B2CTopUp(b2cTopUpOptions)
+			},
+		})
+	},
+}
+
+func init() {
+	b2cCmd.AddCommand(b2cTopUpCmd)
+	b2cTopUpCmd.Flags().StringVar(&b2cTopUpOptions.Receiver, "to", "", "Shortcode whose B2C utility account is loaded (required)")
+	b2cTopUpCmd.Flags().IntVar(&b2cTopUpOptions.Amount, "amount", 0, "Amount to load in KES (required)")
+	b2cTopUpCmd.Flags().StringVar(&b2cTopUpOptions.AccountReference, "account-ref", "", "Optional account reference, up to 13 characters")
+	b2cTopUpCmd.Flags().StringVar(&b2cTopUpOptions.Remarks, "remarks", "B2C Top Up", "Remarks sent with the top-up, 2 to 100 characters")
+	addYesFlag(b2cTopUpCmd)
+	_ = b2cTopUpCmd.MarkFlagRequired("to")
+	_ = b2cTopUpCmd.MarkFlagRequired("amount")
+}
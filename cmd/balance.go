@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+var balanceCmd = &cobra.Command{
+	Use:   "balance",
+	Short: "Check the balance of the business shortcode",
+	Long: `Request the account balance of the business shortcode of the selected profile.
+
+M-Pesa delivers the balance to result_url. To see it here, run a temporary listener with
+--listen and expose it to the internet (e.g. with a tunnel) at --public-url; the
+balance of each account is then printed as a table.
+
+Exit codes:
+  0  balance received (or request accepted, without --listen)
+  1  error
+  4  the balance request failed
+  5  no result received within --timeout`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("error loading config: %w", err)
+		}
+
+		listener, err := startResultListener(config)
+		if err != nil {
+			return err
+		}
+		if listener != nil {
+			defer func() { _ = listener.Close() }()
+		}
+
+		done := make(chan bool)
+		go showSpinner(fmt.Sprintf("Requesting balance of %s", config.BusinessShortcode), done)
+
+		client, err := configClient(config)
+		if err != nil {
+			done <- true
+			<-done
+			fmt.Println("\n❌ Authentication failed.")
+			return err
+		}
+
+		ack, err := client.AccountBalance()
+		done <- true
+		<-done
+
+		if err != nil {
+			fmt.Println("\n❌ Balance request failed.")
+			return fmt.Errorf("error requesting account balance: %w", err)
+		}
+
+		fmt.Println("\n✔ Balance request accepted!")
+		fmt.Printf("Conversation ID: %s\n", ack.ConversationID)
+
+		if listener == nil {
+			fmt.Printf("💡 Tip: The balance will be sent to %s. Use --listen and --public-url to wait for it here.\n", config.ResultURL)
+			return nil
+		}
+
+		result, err := waitForResult(cmd, listener, ack.ConversationID, ack.OriginatorConversationID)
+		if err != nil {
+			return err
+		}
+
+		value, ok := result.Parameter("AccountBalance")
+		if !ok {
+			return fmt.Errorf("the result has no AccountBalance parameter")
+		}
+		balances, err := mpesa.ParseAccountBalance(value)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("--------------------")
+		printBalances(balances)
+		fmt.Println("--------------------")
+
+		return nil
+	},
+}
+
+// printBalances prints balances as an aligned table.
+func printBalances(balances []mpesa.AccountBalance) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "Account\tCurrency\tCurrent\tAvailable\tReserved")
+	for _, b := range balances {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", b.Name, b.Currency, b.Current, b.Available, b.Reserved)
+	}
+	_ = w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(balanceCmd)
+	addListenFlags(balanceCmd)
+}
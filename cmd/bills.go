@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// billsCmd represents the bills parent command
+var billsCmd = &cobra.Command{
+	Use:   "bills",
+	Short: "M-Pesa Bill Manager invoicing",
+	Long: `Parent command for Bill Manager: sending invoices that customers pay to the business
+shortcode of the selected profile, and reconciling their payments.
+
+Opt the shortcode in once with 'mpesa-cli bills optin' before sending invoices.`,
+}
+
+func init() {
+	rootCmd.AddCommand(billsCmd)
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var billsCancelCmd = &cobra.Command{
+	Use:   "cancel <external-reference>...",
+	Short: "Cancel Bill Manager invoices",
+	Long:  `Cancel one or more unpaid invoices by the external reference they were sent with.`,
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		done := make(chan bool)
+		go showSpinner(fmt.Sprintf("Cancelling %d invoices...", len(args)), done)
+
+		client, err := profileClient()
+		if err != nil {
+			done <- true
+			<-done
+			fmt.Println("\n❌ Authentication failed.")
+			return err
+		}
+
+		result, err := client.CancelInvoices(args...)
+		done <- true
+		<-done
+
+		if err != nil {
+			fmt.Println("\n❌ Cancellation failed.")
+			return fmt.Errorf("error cancelling invoices: %w", err)
+		}
+
+		fmt.Println("\n✔ Invoices cancelled!")
+		fmt.Println("--------------------")
+		fmt.Printf("Description: %s\n", result.ResMsg)
+		fmt.Printf("Status: %s\n", valueOrNone(result.StatusMessage))
+		fmt.Println("--------------------")
+
+		return nil
+	},
+}
+
+func init() {
+	billsCmd.AddCommand(billsCancelCmd)
+}
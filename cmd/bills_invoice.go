@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+var (
+	invoice        mpesa.Invoice
+	invoiceItems   []string
+	invoiceFromCSV string
+)
+
+var billsInvoiceCmd = &cobra.Command{
+	Use:   "invoice",
+	Short: "Send Bill Manager invoices",
+	Long: `Send a single invoice described by flags, or many invoices from a CSV file with --from-csv.
+
+The CSV file needs a header row with the columns external_reference, billed_full_name,
+billed_phone_number, billed_period, invoice_name, due_date (YYYY-MM-DD), account_reference
+and amount. Invoices are sent in batches of up to 1000, the limit of the bulk invoicing API.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var invoices []mpesa.Invoice
+		if invoiceFromCSV != "" {
+			var err error
+			invoices, err = readInvoicesCSV(invoiceFromCSV)
+			if err != nil {
+				return err
+			}
+		} else {
+			items, err := parseInvoiceItems(invoiceItems)
+			if err != nil {
+				return err
+			}
+			invoice.InvoiceItems = items
+		}
+
+		done := make(chan bool)
+		go showSpinner("Sending invoices...", done)
+
+		client, err := profileClient()
+		if err != nil {
+			done <- true
+			<-done
+			fmt.Println("\n❌ Authentication failed.")
+			return err
+		}
+
+		if invoiceFromCSV == "" {
+			result, err := client.SendInvoice(invoice)
+			done <- true
+			<-done
+
+			if err != nil {
+				fmt.Println("\n❌ Invoice failed.")
+				return fmt.Errorf("error sending invoice: %w", err)
+			}
+
+			fmt.Println("\n✔ Invoice sent!")
+			fmt.Println("--------------------")
+			fmt.Printf("Description: %s\n", result.ResMsg)
+			fmt.Printf("Status: %s\n", valueOrNone(result.StatusMessage))
+			fmt.Println("--------------------")
+			return nil
+		}
+
+		results, err := client.SendInvoices(invoices)
+		done <- true
+		<-done
+
+		sent := min(len(results)*mpesa.MaxInvoicesPerRequest, len(invoices))
+		if err != nil {
+			fmt.Printf("\n❌ Sent %d of %d invoices.\n", sent, len(invoices))
+			return fmt.Errorf("error sending invoices: %w", err)
+		}
+
+		fmt.Printf("\n✔ Sent %d invoices in %d batches.\n", sent, len(results))
+		return nil
+	},
+}
+
+// readInvoicesCSV reads invoices from a CSV file with one invoice per row.
+func readInvoicesCSV(path string) ([]mpesa.Invoice, error) {
+	records, err := readCSVRecords(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("CSV file %s has no invoices", path)
+	}
+
+	invoices := make([]mpesa.Invoice, len(records))
+	for i, record := range records {
+		amount, err := strconv.Atoi(record["amount"])
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid amount '%s'", i+2, record["amount"])
+		}
+
+		invoices[i] = mpesa.Invoice{
+			ExternalReference: record["external_reference"],
+			BilledFullName:    record["billed_full_name"],
+			BilledPhoneNumber: record["billed_phone_number"],
+			BilledPeriod:      record["billed_period"],
+			InvoiceName:       record["invoice_name"],
+			DueDate:           record["due_date"],
+			AccountReference:  record["account_reference"],
+			Amount:            amount,
+		}
+	}
+
+	return invoices, nil
+}
+
+// parseInvoiceItems parses --item values of the form "name=amount".
+func parseInvoiceItems(values []string) ([]mpesa.InvoiceItem, error) {
+	var items []mpesa.InvoiceItem
+	for _, value := range values {
+		name, amount, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid --item '%s': use name=amount", value)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(amount))
+		if err != nil {
+			return nil, fmt.Errorf("invalid --item '%s': amount must be a whole number", value)
+		}
+		items = append(items, mpesa.InvoiceItem{ItemName: strings.TrimSpace(name), Amount: n})
+	}
+	return items, nil
+}
+
+func init() {
+	billsCmd.AddCommand(billsInvoiceCmd)
+	billsInvoiceCmd.Flags().StringVar(&invoice.ExternalReference, "ref", "", "Unique reference of the invoice in your system")
+	billsInvoiceCmd.Flags().StringVar(&invoice.BilledFullName, "name", "", "Full name of the customer")
+	billsInvoiceCmd.Flags().StringVar(&invoice.BilledPhoneNumber, "phone", "", "Customer phone number, e.g. 0712345678")
+	billsInvoiceCmd.Flags().StringVar(&invoice.BilledPeriod, "period", "", "Billed period, e.g. \"August 2021\"")
+	billsInvoiceCmd.Flags().StringVar(&invoice.InvoiceName, "invoice-name", "", "Name of the invoice, e.g. \"School Fees\"")
+	billsInvoiceCmd.Flags().StringVar(&invoice.DueDate, "due-date", "", "Due date as YYYY-MM-DD")
+	billsInvoiceCmd.Flags().StringVar(&invoice.AccountReference, "account-ref", "", "Account number customers pay the invoice to")
+	billsInvoiceCmd.Flags().IntVar(&invoice.Amount, "amount", 0, "Total amount of the invoice in KES")
+	billsInvoiceCmd.Flags().StringArrayVar(&invoiceItems, "item", nil, "Additional invoice line as name=amount (repeatable)")
+	billsInvoiceCmd.Flags().StringVar(&invoiceFromCSV, "from-csv", "", "CSV file with one invoice per row")
+	billsInvoiceCmd.MarkFlagsMutuallyExclusive("from-csv", "ref")
+	billsInvoiceCmd.MarkFlagsMutuallyExclusive("from-csv", "item")
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+var billOptInOptions mpesa.BillOptInOptions
+
+var billsOptInCmd = &cobra.Command{
+	Use:   "optin",
+	Short: "Onboard the business shortcode to Bill Manager",
+	Long: `Onboard the business shortcode of the selected profile to Bill Manager.
+Payment notifications for invoices are sent to --callback-url; handle them with
+'mpesa-cli bills reconcile'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		done := make(chan bool)
+		go showSpinner("Opting in to Bill Manager...", done)
+
+		client, err := profileClient()
+		if err != nil {
+			done <- true
+			<-done
+			fmt.Println("\n❌ Authentication failed.")
+			return err
+		}
+
+		result, err := client.BillOptIn(billOptInOptions)
+		done <- true
+		<-done
+
+		if err != nil {
+			fmt.Println("\n❌ Opt-in failed.")
+			return fmt.Errorf("error opting in to Bill Manager: %w", err)
+		}
+
+		fmt.Println("\n✔ Opted in to Bill Manager!")
+		fmt.Println("--------------------")
+		fmt.Printf("Response Code: %s\n", result.ResCode)
+		fmt.Printf("Description: %s\n", result.ResMsg)
+		fmt.Printf("App Key: %s\n", valueOrNone(result.AppKey))
+		fmt.Println("--------------------")
+
+		return nil
+	},
+}
+
+func init() {
+	billsCmd.AddCommand(billsOptInCmd)
+	billsOptInCmd.Flags().StringVar(&billOptInOptions.Email, "email", "", "Business email address shown on invoices (required)")
+	billsOptInCmd.Flags().StringVar(&billOptInOptions.OfficialContact, "contact", "", "Business phone number shown on invoices (required)")
+	billsOptInCmd.Flags().BoolVar(&billOptInOptions.SendReminders, "reminders", true, "Send customers SMS reminders for unpaid invoices")
+	billsOptInCmd.Flags().StringVar(&billOptInOptions.Logo, "logo", "", "URL of the business logo shown on invoices")
+	billsOptInCmd.Flags().StringVar(&billOptInOptions.CallbackURL, "callback-url", "", "URL that receives payment notifications (default is callback_url from config)")
+	_ = billsOptInCmd.MarkFlagRequired("email")
+	_ = billsOptInCmd.MarkFlagRequired("contact")
+}
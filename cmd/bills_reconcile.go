@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reconcileAddr     string
+	reconcileInvoices string
+)
+
+var billsReconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Receive invoice payments and reconcile them",
+	Long: `Run the endpoint that receives Bill Manager payment notifications at the callback URL
+given at opt-in. Each payment is acknowledged and reconciled, which sends the
+customer an e-receipt. Expose --listen to the internet at that callback URL.
+
+With --invoices, the CSV file used with 'bills invoice --from-csv' supplies the
+customer and invoice names of each payment, matched by account reference.
+
+Stop the endpoint with Ctrl+C.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		invoices := map[string]*mpesa.Invoice{}
+		if reconcileInvoices != "" {
+			list, err := readInvoicesCSV(reconcileInvoices)
+			if err != nil {
+				return err
+			}
+			for i := range list {
+				invoices[list[i].AccountReference] = &list[i]
+			}
+		}
+
+		client, err := profileClient()
+		if err != nil {
+			fmt.Println("❌ Authentication failed.")
+			return err
+		}
+
+		lookup := func(accountReference string) *mpesa.Invoice {
+			return invoices[accountReference]
+		}
+		report := func(payment mpesa.BillPayment, err error) {
+			if err != nil {
+				fmt.Printf("❌ %s: KES %s from %s for %s not reconciled: %v\n", payment.TransactionID, payment.PaidAmount, payment.MSISDN, payment.AccountReference, err)
+				return
+			}
+			fmt.Printf("✔ %s: KES %s from %s for %s reconciled\n", payment.TransactionID, payment.PaidAmount, payment.MSISDN, payment.AccountReference)
+		}
+
+		listener, err := net.Listen("tcp", reconcileAddr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", reconcileAddr, err)
+		}
+
+		server := &http.Server{Handler: client.NewBillPaymentHandler(lookup, report), ReadHeaderTimeout: 10 * time.Second}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			_ = server.Close()
+		}()
+
+		fmt.Printf("Listening for payment notifications on %s (Ctrl+C to stop)\n", listener.Addr())
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("error serving payment notifications: %w", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	billsCmd.AddCommand(billsReconcileCmd)
+	billsReconcileCmd.Flags().StringVar(&reconcileAddr, "listen", ":8080", "Address to receive payment notifications on")
+	billsReconcileCmd.Flags().StringVar(&reconcileInvoices, "invoices", "", "CSV file of the sent invoices, to name the customer and invoice on receipts")
+}
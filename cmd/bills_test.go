@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestReadInvoicesCSV tests reading bulk invoices from CSV columns
+func TestReadInvoicesCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invoices.csv")
+	csvData := "external_reference,billed_full_name,billed_phone_number,billed_period,invoice_name,due_date,account_reference,amount\n" +
+		"955,John Doe,0722000000,August 2021,School Fees,2021-10-12,Balboa95,800\n"
+	if err := os.WriteFile(path, []byte(csvData), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	invoices, err := readInvoicesCSV(path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(invoices) != 1 || invoices[0].AccountReference != "Balboa95" || invoices[0].Amount != 800 {
+		t.Errorf("unexpected invoices: %+v", invoices)
+	}
+}
+
+// TestParseInvoiceItems tests parsing --item name=amount values
+func TestParseInvoiceItems(t *testing.T) {
+	items, err := parseInvoiceItems([]string{"Transport=200", " Lunch = 150 "})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(items) != 2 || items[1].ItemName != "Lunch" || items[1].Amount != 150 {
+		t.Errorf("unexpected items: %+v", items)
+	}
+
+	for _, invalid := range []string{"Transport", "=200", "Transport=ten"} {
+		if _, err := parseInvoiceItems([]string{invalid}); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
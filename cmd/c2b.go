@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// c2bCmd represents the c2b parent command
+var c2bCmd = &cobra.Command{
+	Use:   "c2b",
+	Short: "Customer to Business (C2B) payments",
+	Long:  `Parent command for C2B operations on the business shortcode of the selected profile.`,
+}
+
+func init() {
+	rootCmd.AddCommand(c2bCmd)
+}
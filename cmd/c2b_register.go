@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+var c2bRegisterOptions mpesa.C2BRegisterOptions
+
+var c2bRegisterCmd = &cobra.Command{
+	Use:   "register",
+	Short: "Register C2B confirmation and validation URLs",
+	Long: `Register the confirmation and validation URLs of the configured business shortcode.
+
+The URLs are checked before sending: production requires https, and Daraja silently
+rejects URLs containing keywords such as "mpesa", "safaricom", "exec", "sql" or "query".
+Registered URLs are remembered so that 'mpesa-cli doctor' can report them.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		done := make(chan bool)
+		go showSpinner("Registering C2B URLs...", done)
+
+		client, err := profileClient()
+		if err != nil {
+			done <- true
+			<-done
+			fmt.Println("\n❌ Authentication failed.")
+			return err
+		}
+
+		result, err := client.RegisterC2BURLs(c2bRegisterOptions)
+		done <- true
+		<-done
+
+		if err != nil {
+			fmt.Println("\n❌ Registration failed.")
+			return fmt.Errorf("error registering C2B URLs: %w", err)
+		}
+
+		fmt.Println("\n✔ C2B URLs registered!")
+		fmt.Println("--------------------")
+		fmt.Printf("Response Code: %s\n", result.ResponseCode)
+		fmt.Printf("Description: %s\n", result.ResponseDescription)
+		fmt.Printf("Originator Conversation ID: %s\n", result.OriginatorConversationID)
+		fmt.Println("--------------------")
+
+		config := client.Config()
+		if err := saveC2BRegistration(config); err != nil {
+			fmt.Println("⚠️ Could not record the registered URLs:", err)
+		}
+
+		return nil
+	},
+}
+
+// saveC2BRegistration records the URLs just registered for the shortcode of config.
+func saveC2BRegistration(config *mpesa.Config) error {
+	path, err := mpesa.DefaultRegistrationsPath()
+	if err != nil {
+		return err
+	}
+
+	responseType := c2bRegisterOptions.ResponseType
+	if responseType == "" {
+		responseType = mpesa.ResponseTypeCompleted
+	}
+
+	return mpesa.NewRegistrationStore(path).Save(mpesa.C2BRegistration{
+		ShortCode:       config.BusinessShortcode,
+		Environment:     config.Environment,
+		ConfirmationURL: c2bRegisterOptions.ConfirmationURL,
+		ValidationURL:   c2bRegisterOptions.ValidationURL,
+		ResponseType:    responseType,
+		RegisteredAt:    time.Now(),
+	})
+}
+
+func init() {
+	c2bCmd.AddCommand(c2bRegisterCmd)
+	c2bRegisterCmd.Flags().StringVar(&c2bRegisterOptions.ConfirmationURL, "confirmation-url", "", "URL that receives payment confirmations (required)")
+	c2bRegisterCmd.Flags().StringVar(&c2bRegisterOptions.ValidationURL, "validation-url", "", "URL that validates payments before they complete (required)")
+	c2bRegisterCmd.Flags().StringVar(&c2bRegisterOptions.ResponseType, "response-type", mpesa.ResponseTypeCompleted, "Action when the validation URL is unreachable: Completed or Cancelled")
+	_ = c2bRegisterCmd.MarkFlagRequired("confirmation-url")
+	_ = c2bRegisterCmd.MarkFlagRequired("validation-url")
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+var (
+	c2bSimulateOptions mpesa.C2BSimulateOptions
+	c2bSimulateType    string
+	c2bSimulateCount   int
+	c2bSimulateCSV     string
+)
+
+var c2bSimulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Simulate customer payments in the sandbox",
+	Long: `Fire simulated customer payments into the sandbox shortcode of the selected profile.
+Simulation is refused when the profile's environment is production.
+
+Use --count to send the same payment several times, or --from-csv to send one payment
+per row of a CSV file with the columns amount, msisdn and bill_ref. Empty or missing
+columns fall back to the values of --amount, --msisdn and --bill-ref.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		commandID, ok := stkTransactionTypes[c2bSimulateType]
+		if !ok {
+			return fmt.Errorf("--type must be paybill or buygoods, got: %s", c2bSimulateType)
+		}
+		c2bSimulateOptions.CommandID = commandID
+
+		payments, err := c2bSimulatePayments()
+		if err != nil {
+			return err
+		}
+
+		config, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("error loading config: %w", err)
+		}
+		if config.Environment == "production" {
+			return fmt.Errorf("refusing to simulate payments: profile '%s' uses the production environment", config.Profile)
+		}
+
+		done := make(chan bool)
+		go showSpinner("Authenticating with M-Pesa...", done)
+
+		client, err := profileClient()
+		done <- true
+		<-done
+
+		if err != nil {
+			fmt.Println("\n❌ Authentication failed.")
+			return err
+		}
+
+		failed := 0
+		for i, payment := range payments {
+			result, err := client.SimulateC2B(payment)
+			if err != nil {
+				failed++
+				fmt.Printf("❌ [%d/%d] KES %d from %s: %v\n", i+1, len(payments), payment.Amount, payment.MSISDN, err)
+				continue
+			}
+			fmt.Printf("✔ [%d/%d] KES %d from %s: %s (%s)\n", i+1, len(payments), payment.Amount, payment.MSISDN,
+				result.ResponseDescription, result.OriginatorConversationID)
+		}
+
+		fmt.Println("--------------------")
+		fmt.Printf("%d of %d simulated payments accepted.\n", len(payments)-failed, len(payments))
+
+		if failed > 0 {
+			return fmt.Errorf("%d simulated payments failed", failed)
+		}
+		return nil
+	},
+}
+
+// c2bSimulatePayments returns the payments to simulate from --from-csv or --count.
+func c2bSimulatePayments() ([]mpesa.C2BSimulateOptions, error) {
+	if c2bSimulateCSV == "" {
+		if c2bSimulateCount < 1 {
+			return nil, fmt.Errorf("--count must be at least 1, got %d", c2bSimulateCount)
+		}
+		payments := make([]mpesa.C2BSimulateOptions, c2bSimulateCount)
+		for i := range payments {
+			payments[i] = c2bSimulateOptions
+		}
+		return payments, nil
+	}
+
+	records, err := readCSVRecords(c2bSimulateCSV)
+	if err != nil {
+		return nil, err
+	}
+
+	payments := make([]mpesa.C2BSimulateOptions, 0, len(records))
+	for i, record := range records {
+		payment := c2bSimulateOptions
+
+		if amount := record["amount"]; amount != "" {
+			payment.Amount, err = strconv.Atoi(amount)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: invalid amount '%s'", i+2, amount)
+			}
+		}
+		if msisdn := record["msisdn"]; msisdn != "" {
+			payment.MSISDN = msisdn
+		}
+		if billRef := record["bill_ref"]; billRef != "" {
+			payment.BillRefNumber = billRef
+		}
+
+		payments = append(payments, payment)
+	}
+
+	if len(payments) == 0 {
+		return nil, fmt.Errorf("CSV file %s has no payments", c2bSimulateCSV)
+	}
+	return payments, nil
+}
+
+func init() {
+	c2bCmd.AddCommand(c2bSimulateCmd)
+	c2bSimulateCmd.Flags().IntVar(&c2bSimulateOptions.Amount, "amount", 0, "Amount paid in KES")
+	c2bSimulateCmd.Flags().StringVar(&c2bSimulateOptions.MSISDN, "msisdn", "254708374149", "Phone number of the paying customer (default is the sandbox test number)")
+	c2bSimulateCmd.Flags().StringVar(&c2bSimulateOptions.BillRefNumber, "bill-ref", "", "Account number entered by the customer (Paybill only)")
+	c2bSimulateCmd.Flags().StringVar(&c2bSimulateType, "type", "paybill", "Payment type: paybill (CustomerPayBillOnline) or buygoods (CustomerBuyGoodsOnline)")
+	c2bSimulateCmd.Flags().IntVar(&c2bSimulateCount, "count", 1, "Number of identical payments to simulate")
+	c2bSimulateCmd.Flags().StringVar(&c2bSimulateCSV, "from-csv", "", "CSV file with one payment per row (columns: amount, msisdn, bill_ref)")
+	c2bSimulateCmd.MarkFlagsMutuallyExclusive("count", "from-csv")
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+)
+
+// TestC2BSimulatePaymentsFromCSV tests that CSV rows become payments, with --msisdn as the fallback number
+func TestC2BSimulatePaymentsFromCSV(t *testing.T) {
+	oldOptions, oldCSV := c2bSimulateOptions, c2bSimulateCSV
+	defer func() { c2bSimulateOptions, c2bSimulateCSV = oldOptions, oldCSV }()
+
+	path := filepath.Join(t.TempDir(), "payments.csv")
+	csvData := "Amount,MSISDN,Bill_Ref\n100,254708374149,INV-1\n250,,INV-2\n"
+	if err := os.WriteFile(path, []byte(csvData), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c2bSimulateOptions = mpesa.C2BSimulateOptions{MSISDN: "254700000000", CommandID: mpesa.CustomerPayBillOnline}
+	c2bSimulateCSV = path
+
+	payments, err := c2bSimulatePayments()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(payments) != 2 {
+		t.Fatalf("expected 2 payments, got %d", len(payments))
+	}
+	if payments[0].Amount != 100 || payments[0].MSISDN != "254708374149" || payments[0].BillRefNumber != "INV-1" {
+		t.Errorf("unexpected first payment: %+v", payments[0])
+	}
+	if payments[1].MSISDN != "254700000000" {
+		t.Errorf("expected empty msisdn to fall back to --msisdn, got %s", payments[1].MSISDN)
+	}
+}
+
+// TestC2BSimulatePaymentsRejectsBadAmount tests that an invalid amount is reported with its CSV row
+func TestC2BSimulatePaymentsRejectsBadAmount(t *testing.T) {
+	oldCSV := c2bSimulateCSV
+	defer func() { c2bSimulateCSV = oldCSV }()
+
+	path := filepath.Join(t.TempDir(), "payments.csv")
+	if err := os.WriteFile(path, []byte("amount\nten\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c2bSimulateCSV = path
+
+	_, err := c2bSimulatePayments()
+	if err == nil || !strings.Contains(err.Error(), "row 2") {
+		t.Errorf("expected row 2 amount error, got %v", err)
+	}
+}
+
+// TestC2BSimulatePaymentsCount tests that --count repeats the payment given by the flags
+func TestC2BSimulatePaymentsCount(t *testing.T) {
+	oldOptions, oldCount := c2bSimulateOptions, c2bSimulateCount
+	defer func() { c2bSimulateOptions, c2bSimulateCount = oldOptions, oldCount }()
+
+	c2bSimulateOptions = mpesa.C2BSimulateOptions{Amount: 10, MSISDN: "254708374149"}
+	c2bSimulateCount = 3
+
+	payments, err := c2bSimulatePayments()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(payments) != 3 {
+		t.Errorf("expected 3 payments, got %d", len(payments))
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+)
+
+// userAgent identifies the CLI and its version to the M-Pesa API.
+func userAgent() string {
+	return "mpesa-cli/" + version
+}
+
+// activeProfile returns the profile selected with --profile, MPESA_PROFILE or default_profile.
+func activeProfile() string {
+	return mpesa.ResolveProfile(profileName)
+}
+
+// loadConfig returns the configuration of the active profile.
+func loadConfig() (*mpesa.Config, error) {
+	return mpesa.LoadProfileConfig(activeProfile())
+}
+
+// newClient creates an API client for config that authenticates with the given consumer credentials.
+// Extra options are applied after the defaults.
+// Access tokens are cached between invocations when the user cache directory is available.
+func newClient(config *mpesa.Config, consumerKey, consumerSecret string, extra ...mpesa.Option) *mpesa.Client {
+	opts := []mpesa.Option{
+		mpesa.WithCredentials(consumerKey, consumerSecret),
+		mpesa.WithUserAgent(userAgent()),
+	}
+
+	if path, err := mpesa.DefaultTokenCachePath(); err == nil {
+		opts = append(opts, mpesa.WithTokenCache(mpesa.NewTokenCache(path)))
+	}
+
+	return mpesa.NewClient(config, append(opts, extra...)...)
+}
+
+// profileClient loads the active profile's configuration and credentials and returns a
+// client that has already authenticated, so that API errors are not confused with login problems.
+func profileClient(opts ...mpesa.Option) (*mpesa.Client, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading config: %w", err)
+	}
+
+	return configClient(config, opts...)
+}
+
+// configClient is profileClient for an already loaded (and possibly adjusted) configuration.
+// If an initiator password is stored for the profile, the security credential is generated from it.
+func configClient(config *mpesa.Config, opts ...mpesa.Option) (*mpesa.Client, error) {
+	consumerKey, consumerSecret, err := mpesa.GetProfileCredentials(config.Profile)
+	if err != nil {
+		return nil, fmt.Errorf("error getting credentials: %w", err)
+	}
+
+	if err := mpesa.ResolveSecurityCredential(config); err != nil {
+		return nil, fmt.Errorf("error generating security credential: %w", err)
+	}
+
+	client := newClient(config, consumerKey, consumerSecret, opts...)
+	if _, err := client.Token(); err != nil {
+		return nil, fmt.Errorf("error getting access token: %w", err)
+	}
+
+	return client, nil
+}
+
+// credentialBackendName returns the name of the credential backend in use, or a
+// description of why it could not be opened.
+func credentialBackendName() string {
+	store, err := mpesa.ActiveCredentialStore()
+	if err != nil {
+		return fmt.Sprintf("unavailable (%v)", err)
+	}
+	return store.Name()
+}
+
+// recordVerification notes that M-Pesa accepted the credentials of profile in environment.
+// The record is informational, so failing to write it is ignored.
+func recordVerification(profile, environment string) {
+	path, err := mpesa.DefaultVerificationsPath()
+	if err != nil {
+		return
+	}
+	_ = mpesa.NewVerificationStore(path).Record(profile, mpesa.Verification{Environment: environment, VerifiedAt: time.Now()})
+}
+
+// forgetCachedTokens removes the access tokens cached for consumerKey in every
+// environment, including those of custom base URLs.
+func forgetCachedTokens(consumerKey string) error {
+	path, err := mpesa.DefaultTokenCachePath()
+	if err != nil {
+		return err
+	}
+	return mpesa.NewTokenCache(path).DeleteConsumer(consumerKey)
+}
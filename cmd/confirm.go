@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// assumeYes is set by the --yes flag of commands that move money
+var assumeYes bool
+
+// addYesFlag registers the --yes flag that skips the production confirmation prompt.
+func addYesFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Do not ask for confirmation in production")
+}
+
+// confirmProduction asks the user to confirm summary before money moves in production.
+// Sandbox profiles and --yes skip the prompt. Without a terminal to ask on, the
+// action is refused rather than performed unconfirmed.
+func confirmProduction(config *mpesa.Config, summary string) error {
+	if config.Environment != "production" || assumeYes {
+		return nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf("refusing to %s in production without confirmation; pass --yes to proceed", summary)
+	}
+
+	if !confirm(os.Stdin, os.Stdout, fmt.Sprintf("⚠️  Profile '%s' is production. %s?", config.Profile, capitalize(summary))) {
+		return fmt.Errorf("aborted")
+	}
+	return nil
+}
+
+// confirm prints prompt and reports whether the answer read from in is yes.
+func confirm(in io.Reader, out io.Writer, prompt string) bool {
+	_, _ = fmt.Fprintf(out, "%s [y/N]: ", prompt)
+
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// confirmTypedProduction asks the user to type expected to confirm summary in production.
+// It is used for actions that are expensive to get wrong, so --yes does not skip it.
+func confirmTypedProduction(config *mpesa.Config, summary, expected string) error {
+	if config.Environment != "production" {
+		return nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf("refusing to %s in production without a terminal to confirm on", summary)
+	}
+
+	prompt := fmt.Sprintf("⚠️  Profile '%s' is production. Type %s to %s", config.Profile, expected, summary)
+	if !confirmTyped(os.Stdin, os.Stdout, prompt, expected) {
+		return fmt.Errorf("aborted")
+	}
+	return nil
+}
+
+// confirmTyped prints prompt and reports whether the answer read from in is exactly expected.
+func confirmTyped(in io.Reader, out io.Writer, prompt, expected string) bool {
+	_, _ = fmt.Fprintf(out, "%s: ", prompt)
+
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	return strings.TrimSpace(answer) == expected
+}
+
+// capitalize upper-cases the first letter of s.
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
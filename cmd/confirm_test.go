@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+)
+
+// TestConfirm tests reading yes/no answers
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		answer   string
+		expected bool
+	}{
+		{answer: "y\n", expected: true},
+		{answer: "YES\n", expected: true},
+		{answer: "n\n", expected: false},
+		{answer: "\n", expected: false},
+		{answer: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if got := confirm(strings.NewReader(tt.answer), &out, "Pay?"); got != tt.expected {
+			t.Errorf("answer %q: expected %v, got %v", tt.answer, tt.expected, got)
+		}
+		if !strings.Contains(out.String(), "Pay? [y/N]") {
+			t.Errorf("expected prompt, got %q", out.String())
+		}
+	}
+}
+
+// TestConfirmProduction tests that only production without --yes needs confirmation
+func TestConfirmProduction(t *testing.T) {
+	defer func() { assumeYes = false }()
+
+	if err := confirmProduction(&mpesa.Config{Environment: "sandbox"}, "pay"); err != nil {
+		t.Errorf("expected sandbox to need no confirmation, got %v", err)
+	}
+
+	assumeYes = true
+	if err := confirmProduction(&mpesa.Config{Environment: "production"}, "pay"); err != nil {
+		t.Errorf("expected --yes to skip confirmation, got %v", err)
+	}
+
+	// Tests do not run on a terminal, so production without --yes is refused
+	assumeYes = false
+	err := confirmProduction(&mpesa.Config{Environment: "production"}, "pay KES 10")
+	if err == nil || !strings.Contains(err.Error(), "--yes") {
+		t.Errorf("expected refusal without --yes, got %v", err)
+	}
+}
+
+// TestConfirmTyped tests that only the exact expected text confirms
+func TestConfirmTyped(t *testing.T) {
+	tests := []struct {
+		answer   string
+		expected bool
+	}{
+		{answer: "OEI2AK4Q16\n", expected: true},
+		{answer: "  OEI2AK4Q16  \n", expected: true},
+		{answer: "oei2ak4q16\n", expected: false},
+		{answer: "y\n", expected: false},
+		{answer: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if got := confirmTyped(strings.NewReader(tt.answer), &out, "Type OEI2AK4Q16", "OEI2AK4Q16"); got != tt.expected {
+			t.Errorf("answer %q: expected %v, got %v", tt.answer, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// certificatePath is set by the --cert flag of the credential commands
+var certificatePath string
+
+// credentialCmd represents the credential parent command
+var credentialCmd = &cobra.Command{
+	Use:   "credential",
+	Short: "Generate the SecurityCredential of the initiator",
+	Long: `Parent command for managing the SecurityCredential sent with B2C, B2B, balance,
+transaction status and reversal requests.
+
+The SecurityCredential is the initiator password encrypted with the public certificate
+Safaricom publishes for each environment. Certificates bundled into the binary are used
+by default; pass --cert or set certificate_path to use a downloaded certificate instead.`,
+}
+
+// printCertificate prints the subject and validity of cert to stderr, warning if it has expired.
+func printCertificate(cert *x509.Certificate) {
+	fmt.Fprintf(os.Stderr, "Certificate: %s\n", cert.Subject)
+	fmt.Fprintf(os.Stderr, "Expires: %s\n", cert.NotAfter.Format("2006-01-02"))
+	if time.Now().After(cert.NotAfter) {
+		fmt.Fprintln(os.Stderr, "⚠️  The certificate has expired; M-Pesa will reject credentials encrypted with it.")
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(credentialCmd)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+// credentialEnvironment is set by the --env flag of credential encrypt
+var credentialEnvironment string
+
+var credentialEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt an initiator password into a SecurityCredential",
+	Long: `Encrypt the initiator password with the certificate of the active profile's environment
+(or --env) and print the resulting SecurityCredential, e.g. to paste into security_credential.
+
+The password is prompted for on a terminal, or read from the first line of stdin otherwise.
+The certificate subject and expiry are printed to stderr.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("error loading config: %w", err)
+		}
+
+		environment := config.Environment
+		if credentialEnvironment != "" {
+			environment = credentialEnvironment
+		}
+		if environment != "sandbox" && environment != "production" {
+			return fmt.Errorf("--env must be sandbox or production, got: %s", environment)
+		}
+
+		path := config.CertificatePath
+		if certificatePath != "" {
+			path = certificatePath
+		}
+
+		cert, err := mpesa.LoadCertificate(environment, path)
+		if err != nil {
+			return err
+		}
+		printCertificate(cert)
+
+		password, err := readSecret("? Initiator Password: ")
+		if err != nil {
+			return fmt.Errorf("failed to read password: %w", err)
+		}
+
+		credential, err := mpesa.EncryptSecurityCredential(password, cert)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(credential)
+		return nil
+	},
+}
+
+func init() {
+	credentialCmd.AddCommand(credentialEncryptCmd)
+	credentialEncryptCmd.Flags().StringVar(&credentialEnvironment, "env", "", "Environment whose certificate to use: sandbox or production (default is the profile's environment)")
+	credentialEncryptCmd.Flags().StringVar(&certificatePath, "cert", "", "Certificate to encrypt with (default is certificate_path, or the bundled certificate)")
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+var credentialStoreCmd = &cobra.Command{
+	Use:   "store",
+	Short: "Store the initiator password with the profile's credentials",
+	Long: `Store the initiator password of the selected profile in the credential store (the system
+keychain unless another credential backend is configured).
+
+Commands that need a SecurityCredential then generate it on demand by encrypting the
+password with the certificate of the profile's environment, and security_credential
+in the config file is no longer used. If the certificate cannot be loaded, the password
+is not kept.
+
+The password is prompted for on a terminal, or read from the first line of stdin otherwise.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile := activeProfile()
+		if err := mpesa.ValidateProfileName(profile); err != nil {
+			return err
+		}
+
+		password, err := readSecret("? Initiator Password: ")
+		if err != nil {
+			return fmt.Errorf("failed to read password: %w", err)
+		}
+		if password == "" {
+			return fmt.Errorf("initiator password cannot be empty")
+		}
+
+		// The password is stored first: without security_credential, a production
+		// profile only becomes valid once it is
+		if err := mpesa.SetProfileInitiatorPassword(profile, password); err != nil {
+			return err
+		}
+
+		if err := checkProfileCertificate(); err != nil {
+			_ = mpesa.DeleteProfileInitiatorPassword(profile)
+			return err
+		}
+
+		fmt.Printf("✅ Initiator password stored for profile '%s'.\n", profile)
+		return nil
+	},
+}
+
+// checkProfileCertificate loads the certificate the active profile generates its
+// security credential with and prints its details.
+func checkProfileCertificate() error {
+	config, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	cert, err := mpesa.LoadCertificate(config.Environment, config.CertificatePath)
+	if err != nil {
+		return err
+	}
+	printCertificate(cert)
+	return nil
+}
+
+func init() {
+	credentialCmd.AddCommand(credentialStoreCmd)
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestCredentialEncryptRejectsUnknownEnvironment tests that --env is validated before any certificate is read
+func TestCredentialEncryptRejectsUnknownEnvironment(t *testing.T) {
+	oldEnv := credentialEnvironment
+	defer func() { credentialEnvironment = oldEnv }()
+
+	credentialEnvironment = "staging"
+	err := credentialEncryptCmd.RunE(credentialEncryptCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "--env") {
+		t.Errorf("expected --env error, got %v", err)
+	}
+}
+
+// TestCredentialCommandStructure tests that the credential subcommands are registered
+func TestCredentialCommandStructure(t *testing.T) {
+	for _, name := range []string{"encrypt", "store"} {
+		if cmd, _, err := credentialCmd.Find([]string{name}); err != nil || cmd.Name() != name {
+			t.Errorf("expected credential %s command, got %v", name, err)
+		}
+	}
+}
+
+// TestReadSecretKeepsSpaces tests that only the line ending is removed from a piped secret
+func TestReadSecretKeepsSpaces(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldStdin := os.Stdin
+	os.Stdin = reader
+	defer func() { os.Stdin = oldStdin; _ = reader.Close() }()
+
+	_, _ = writer.WriteString(" pass word \r\n")
+	_ = writer.Close()
+
+	secret, err := readSecret("? Initiator Password: ")
+	if err != nil || secret != " pass word " {
+		t.Errorf("expected %q, got %q %v", " pass word ", secret, err)
+	}
+}
@@ -12,54 +12,82 @@ import (
 
 // doctorCheck runs the health check logic with injectable dependencies for testability.
 func doctorCheck(
+	profile string,
+	backend string,
 	getCreds func() (string, string, error),
-	getToken func(url, key, secret string) error,
+	getToken func(baseURL, key, secret string) error,
+	getRegistration func() (*mpesa.C2BRegistration, error),
 	print func(...interface{}),
 ) {
 	print("🔎 Running M-Pesa CLI Environment Health Check...")
+	print("👤 Profile:", profile)
+	print("🔐 Credential backend:", backend)
 
-	// 1. Check credentials in keychain
+	// 1. Check credentials in the credential store
 	consumerKey, consumerSecret, err := getCreds()
 	if err != nil {
-		print("❌ Credentials not found in keychain:", err)
+		print("❌ Credentials not found:", err)
 		return
 	}
-	print("✅ Credentials found in keychain.")
+	print("✅ Credentials found.")
 
 	// 2. Try to fetch auth token for sandbox
-	sandboxURL := "https://sandbox.safaricom.co.ke/oauth/v1/generate?grant_type=client_credentials"
-	if err := getToken(sandboxURL, consumerKey, consumerSecret); err != nil {
+	if err := getToken(mpesa.SandboxBaseURL, consumerKey, consumerSecret); err != nil {
 		print("❌ Sandbox environment: Failed to fetch auth token:", err)
 	} else {
 		print("✅ Sandbox environment: Auth token fetched successfully.")
 	}
 
 	// 3. Try to fetch auth token for production
-	prodURL := "https://api.safaricom.co.ke/oauth/v1/generate?grant_type=client_credentials"
-	if err := getToken(prodURL, consumerKey, consumerSecret); err != nil {
+	if err := getToken(mpesa.ProductionBaseURL, consumerKey, consumerSecret); err != nil {
 		print("❌ Production environment: Failed to fetch auth token:", err)
 	} else {
 		print("✅ Production environment: Auth token fetched successfully.")
 	}
 
+	// 4. Report the C2B URLs registered for the profile's shortcode
+	reg, err := getRegistration()
+	switch {
+	case err != nil:
+		print("⚠️ C2B URLs: Could not look up registered URLs:", err)
+	case reg == nil:
+		print("ℹ️ C2B URLs: None registered with mpesa-cli for this shortcode.")
+	default:
+		print(fmt.Sprintf("✅ C2B URLs: Registered for shortcode %s (%s) on %s.", reg.ShortCode, reg.Environment, reg.RegisteredAt.Format("2006-01-02 15:04")))
+		print("   Confirmation URL:", reg.ConfirmationURL)
+		print("   Validation URL:", reg.ValidationURL)
+		print("   Response Type:", reg.ResponseType)
+	}
+
 	print("\nHealth check complete.")
 }
 
 var doctorCmd = &cobra.Command{
 	Use:   "doctor",
 	Short: "Run environment and credential health checks",
-	Long:  `Performs diagnostic checks on your configuration and credentials for both sandbox and production environments.`,
+	Long:  `Performs diagnostic checks on the credentials of the selected profile for both sandbox and production environments.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		profile := activeProfile()
 		doctorCheck(
-			mpesa.GetCredentials,
-			func(url, key, secret string) error {
-				// Temporarily set AuthURL for each call
-				oldURL := mpesa.AuthURL
-				mpesa.AuthURL = url
-				defer func() { mpesa.AuthURL = oldURL }()
-				_, err := mpesa.GetAccessToken(key, secret)
+			profile,
+			credentialBackendName(),
+			func() (string, string, error) { return mpesa.GetProfileCredentials(profile) },
+			func(baseURL, key, secret string) error {
+				client := mpesa.NewClient(nil, mpesa.WithBaseURL(baseURL), mpesa.WithUserAgent(userAgent()))
+				_, err := client.GetAccessToken(key, secret)
 				return err
 			},
+			func() (*mpesa.C2BRegistration, error) {
+				config, err := mpesa.LoadProfileConfig(profile)
+				if err != nil {
+					return nil, err
+				}
+				path, err := mpesa.DefaultRegistrationsPath()
+				if err != nil {
+					return nil, err
+				}
+				return mpesa.NewRegistrationStore(path).Get(config.Environment, config.BusinessShortcode)
+			},
 			func(args ...interface{}) { fmt.Println(args...) },
 		)
 	},
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
 )
 
 // --- Mocks ---var loginCmd = &cobra.Command{
@@ -14,13 +17,17 @@ var (
 	mockCredsError = func() (string, string, error) { return "", "", errors.New("no creds") }
 	mockTokenOK    = func(url, key, secret string) error { return nil }
 	mockTokenError = func(url, key, secret string) error { return errors.New("bad creds") }
+	mockNoC2BURLs  = func() (*mpesa.C2BRegistration, error) { return nil, nil }
 )
 
 func TestDoctorCheckCredentialsMissing(t *testing.T) {
 	var output []string
 	doctorCheck(
+		"default",
+		"memory",
 		mockCredsError,
 		mockTokenOK,
+		mockNoC2BURLs,
 		func(args ...interface{}) { output = append(output, sprint(args...)) },
 	)
 	found := false
@@ -37,8 +44,11 @@ func TestDoctorCheckCredentialsMissing(t *testing.T) {
 func TestDoctorCheckTokenFailures(t *testing.T) {
 	var output []string
 	doctorCheck(
+		"default",
+		"memory",
 		mockCredsOK,
 		mockTokenError,
+		mockNoC2BURLs,
 		func(args ...interface{}) { output = append(output, sprint(args...)) },
 	)
 	sandboxFail, prodFail := false, false
@@ -58,8 +68,11 @@ func TestDoctorCheckTokenFailures(t *testing.T) {
 func TestDoctorCheckAllOK(t *testing.T) {
 	var output []string
 	doctorCheck(
+		"default",
+		"memory",
 		mockCredsOK,
 		mockTokenOK,
+		mockNoC2BURLs,
 		func(args ...interface{}) { output = append(output, sprint(args...)) },
 	)
 	ok := false
@@ -73,6 +86,37 @@ func TestDoctorCheckAllOK(t *testing.T) {
 	}
 }
 
+// TestDoctorCheckReportsC2BURLs tests that doctor prints the registered C2B URLs
+func TestDoctorCheckReportsC2BURLs(t *testing.T) {
+	var output []string
+	doctorCheck(
+		"default",
+		"memory",
+		mockCredsOK,
+		mockTokenOK,
+		func() (*mpesa.C2BRegistration, error) {
+			return &mpesa.C2BRegistration{
+				ShortCode:       "600986",
+				Environment:     "sandbox",
+				ConfirmationURL: "https://example.com/confirmation",
+				ValidationURL:   "https://example.com/validation",
+				ResponseType:    mpesa.ResponseTypeCompleted,
+				RegisteredAt:    time.Now(),
+			}, nil
+		},
+		func(args ...interface{}) { output = append(output, sprint(args...)) },
+	)
+	found := false
+	for _, line := range output {
+		if strings.Contains(line, "https://example.com/confirmation") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected registered confirmation URL in output")
+	}
+}
+
 // sprint joins args like fmt.Sprint but returns a string
 func sprint(args ...interface{}) string {
 	var sb strings.Builder
@@ -0,0 +1,31 @@
+package cmd
+
+import "fmt"
+
+// Process exit codes for outcomes that scripts may want to branch on.
+// Any other error exits with 1.
+const (
+	exitCancelled = 2 // the customer cancelled the request
+	exitTimedOut  = 3 // the customer did not respond in time
+	exitFailed    = 4 // the transaction completed with a failure result
+	exitPending   = 5 // the transaction has not reached a final state yet
+)
+
+// exitError is an error that makes Execute exit with a specific code.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode wraps a formatted error so that the process exits with code.
+func withExitCode(code int, format string, args ...interface{}) error {
+	return &exitError{code: code, err: fmt.Errorf(format, args...)}
+}
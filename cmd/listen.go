@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+// Flags of commands that can wait for their result on a temporary local listener
+var (
+	listenAddr    string
+	publicURL     string
+	resultTimeout time.Duration
+)
+
+// addListenFlags registers --listen, --public-url and --timeout on cmd.
+func addListenFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&listenAddr, "listen", "", "Wait for the result on a temporary local listener at this address, e.g. :8080")
+	cmd.Flags().StringVar(&publicURL, "public-url", "", "Public URL that forwards to the --listen address (used as result and timeout URL)")
+	cmd.Flags().DurationVar(&resultTimeout, "timeout", 2*time.Minute, "Maximum time to wait for the result with --listen")
+}
+
+// startResultListener starts the listener requested with --listen and points the
+// result and timeout URLs of config at --public-url. It returns nil if --listen
+// was not given.
+func startResultListener(config *mpesa.Config) (*mpesa.ResultListener, error) {
+	if listenAddr == "" {
+		return nil, nil
+	}
+	if publicURL == "" {
+		return nil, fmt.Errorf("--public-url is required with --listen: M-Pesa must be able to reach the listener")
+	}
+	if err := mpesa.ValidateCallbackURL(publicURL, config.Environment); err != nil {
+		return nil, err
+	}
+
+	listener, err := mpesa.ListenForResults(listenAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	config.ResultURL = publicURL
+	config.QueueTimeOutURL = publicURL
+	return listener, nil
+}
+
+// waitForResult waits on listener for the result of the request acknowledged with
+// conversationID and originatorConversationID, and turns timeouts and failed results
+// into errors with the matching exit code.
+func waitForResult(cmd *cobra.Command, listener *mpesa.ResultListener, conversationID, originatorConversationID string) (*mpesa.Result, error) {
+	done := make(chan bool)
+	go showSpinner(fmt.Sprintf("Waiting for the result on %s", listener.Addr()), done)
+
+	result, err := listener.WaitFor(conversationID, originatorConversationID, resultTimeout)
+	done <- true
+	<-done
+
+	// Outcomes below are reported through the exit code, not as usage errors
+	cmd.SilenceUsage = true
+
+	if errors.Is(err, mpesa.ErrResultTimeout) {
+		fmt.Println("⏳ No result received yet.")
+		return nil, withExitCode(exitPending, "no result for %s within %s", conversationID, resultTimeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !result.Succeeded() {
+		fmt.Println("❌ Request failed.")
+		return nil, withExitCode(exitFailed, "request failed with result code %s: %s", result.ResultCode, result.ResultDesc)
+	}
+
+	return result, nil
+}
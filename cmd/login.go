@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"os"
 	"strings"
-	"syscall"
 
 	"github.com/martwebber/mpesa-cli/pkg/mpesa"
 
@@ -11,65 +13,214 @@ import (
 	"golang.org/x/term"
 )
 
+var (
+	withPasskey      bool
+	consumerKeyStdin bool
+	loginFromEnv     bool
+	skipVerify       bool
+	loginEnvironment string
+)
+
 var loginCmd = &cobra.Command{
 	Use:   "login",
 	Short: "Authenticate with the M-Pesa API",
 	Long: `The login command securely prompts for your M-Pesa Consumer Key
-and Consumer Secret, validates them, and stores them in your system's keychain.`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Println("First, please enter your credentials from the Daraja Portal.")
+and Consumer Secret, validates them, and stores them in your system's keychain.
+
+Credentials are stored for the selected profile (see --profile). With --passkey,
+you are also asked for the Lipa Na M-Pesa Online passkey used by 'mpesa-cli stk'.
 
-		fmt.Print("? Consumer Key: ")
-		var consumerKey string
-		_, _ = fmt.Scanln(&consumerKey)
-		consumerKey = strings.TrimSpace(consumerKey)
+The credentials are validated against the OAuth endpoint of the profile's environment,
+or of --env. Use --skip-verify to store them without contacting M-Pesa.
 
-		// Validate consumer key
-		if consumerKey == "" {
-			fmt.Println("❌ Consumer Key cannot be empty")
-			return fmt.Errorf("consumer key cannot be empty")
+For CI and other non-interactive use, pass the credentials without a terminal:
+  --consumer-key-stdin  read the consumer key, secret and (with --passkey) passkey
+                        from the first lines of stdin
+  --from-env            read them from MPESA_CONSUMER_KEY, MPESA_CONSUMER_SECRET
+                        and (with --passkey) MPESA_PASSKEY
+
+On machines without a keychain, select another credential backend with
+credential_backend in the config file or MPESA_CREDENTIAL_BACKEND: file (encrypted
+with MPESA_CREDENTIAL_PASSPHRASE), env (read-only) or memory.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile := activeProfile()
+		if err := mpesa.ValidateProfileName(profile); err != nil {
+			return err
 		}
 
-		fmt.Print("? Consumer Secret: ")
-		byteSecret, err := term.ReadPassword(int(syscall.Stdin))
+		environment, err := targetEnvironment(loginEnvironment)
 		if err != nil {
-			return fmt.Errorf("failed to read secret: %w", err)
+			return err
 		}
-		consumerSecret := strings.TrimSpace(string(byteSecret))
-		fmt.Println()
 
-		// Validate consumer secret
-		if consumerSecret == "" {
-			fmt.Println("❌ Consumer Secret cannot be empty")
-			return fmt.Errorf("consumer secret cannot be empty")
+		var consumerKey, consumerSecret, passkey string
+		switch {
+		case loginFromEnv:
+			consumerKey, consumerSecret, passkey, err = credentialsFromEnv()
+		case consumerKeyStdin:
+			consumerKey, consumerSecret, passkey, err = credentialsFromReader(os.Stdin)
+		default:
+			consumerKey, consumerSecret, err = promptCredentials(profile)
+		}
+		if err != nil {
+			return err
 		}
 
-		done := make(chan bool)
-		go showSpinner("Authenticating with M-Pesa...", done)
+		if skipVerify {
+			fmt.Println("⚠️  Skipping verification; the credentials are stored unchecked.")
+		} else {
+			done := make(chan bool)
+			go showSpinner(fmt.Sprintf("Authenticating with M-Pesa (%s)...", environment), done)
 
-		_, err = mpesa.GetAccessToken(consumerKey, consumerSecret)
-		done <- true
-		<-done
+			client := mpesa.NewClient(&mpesa.Config{Environment: environment}, mpesa.WithUserAgent(userAgent()))
+			_, err = client.GetAccessToken(consumerKey, consumerSecret)
+			done <- true
+			<-done
 
-		if err != nil {
-			fmt.Println("\n❌ Authentication failed.")
-			return fmt.Errorf("authentication failed: %w", err)
+			if err != nil {
+				fmt.Println("\n❌ Authentication failed.")
+				return fmt.Errorf("authentication failed against %s: %w", environment, err)
+			}
+
+			fmt.Println("\n✔ Authentication successful!")
 		}
 
-		fmt.Println("\n✔ Authentication successful!")
+		// Tokens cached for a previous secret of the same key must not outlive it
+		_ = forgetCachedTokens(consumerKey)
 
-		err = mpesa.SetCredentials(consumerKey, consumerSecret)
+		err = mpesa.SetProfileCredentials(profile, consumerKey, consumerSecret)
 		if err != nil {
 			return fmt.Errorf("failed to store credentials: %w", err)
 		}
+		if !skipVerify {
+			recordVerification(profile, environment)
+		}
+
+		if withPasskey {
+			if passkey == "" {
+				passkey, err = readSecret("? Lipa Na M-Pesa Online Passkey: ")
+				if err != nil {
+					return fmt.Errorf("failed to read passkey: %w", err)
+				}
+				passkey = strings.TrimSpace(passkey)
+			}
+
+			if passkey == "" {
+				fmt.Println("❌ Passkey cannot be empty")
+				return fmt.Errorf("passkey cannot be empty")
+			}
+
+			if err := mpesa.SetProfilePasskey(profile, passkey); err != nil {
+				return fmt.Errorf("failed to store passkey: %w", err)
+			}
+		}
 
-		fmt.Println("✅ Your credentials have been securely stored.")
+		fmt.Printf("✅ Your credentials have been securely stored (backend: %s).\n", credentialBackendName())
 		fmt.Println("💡 Tip: Run `mpesa doctor` to check your connection.")
 
 		return nil
 	},
 }
 
+// targetEnvironment returns the environment to verify credentials against:
+// environment (the value of --env) if set, or else the environment of the active profile.
+func targetEnvironment(environment string) (string, error) {
+	if environment != "" {
+		if environment != "sandbox" && environment != "production" {
+			return "", fmt.Errorf("--env must be sandbox or production, got: %s", environment)
+		}
+		return environment, nil
+	}
+
+	environment, err := mpesa.ProfileEnvironment(activeProfile())
+	if err != nil {
+		return "", fmt.Errorf("error loading config (pass --env to choose the environment): %w", err)
+	}
+	return environment, nil
+}
+
+// promptCredentials asks for the consumer key and secret on the terminal.
+func promptCredentials(profile string) (string, string, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", "", fmt.Errorf("stdin is not a terminal; use --consumer-key-stdin or --from-env to log in non-interactively")
+	}
+
+	fmt.Printf("First, please enter your credentials from the Daraja Portal (profile: %s).\n", profile)
+
+	fmt.Print("? Consumer Key: ")
+	consumerKey, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	consumerKey = strings.TrimSpace(consumerKey)
+
+	// Validate consumer key
+	if consumerKey == "" {
+		fmt.Println("❌ Consumer Key cannot be empty")
+		return "", "", fmt.Errorf("consumer key cannot be empty")
+	}
+
+	consumerSecret, err := readSecret("? Consumer Secret: ")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read secret: %w", err)
+	}
+	consumerSecret = strings.TrimSpace(consumerSecret)
+
+	// Validate consumer secret
+	if consumerSecret == "" {
+		fmt.Println("❌ Consumer Secret cannot be empty")
+		return "", "", fmt.Errorf("consumer secret cannot be empty")
+	}
+
+	return consumerKey, consumerSecret, nil
+}
+
+// credentialsFromEnv reads the consumer key, secret and, with --passkey, passkey from the environment.
+func credentialsFromEnv() (string, string, string, error) {
+	consumerKey := strings.TrimSpace(os.Getenv("MPESA_CONSUMER_KEY"))
+	consumerSecret := strings.TrimSpace(os.Getenv("MPESA_CONSUMER_SECRET"))
+	if consumerKey == "" || consumerSecret == "" {
+		return "", "", "", fmt.Errorf("--from-env requires MPESA_CONSUMER_KEY and MPESA_CONSUMER_SECRET to be set")
+	}
+
+	var passkey string
+	if withPasskey {
+		passkey = strings.TrimSpace(os.Getenv("MPESA_PASSKEY"))
+		if passkey == "" {
+			return "", "", "", fmt.Errorf("--from-env with --passkey requires MPESA_PASSKEY to be set")
+		}
+	}
+
+	return consumerKey, consumerSecret, passkey, nil
+}
+
+// credentialsFromReader reads the consumer key, secret and, with --passkey, passkey
+// from consecutive lines of in.
+func credentialsFromReader(in io.Reader) (string, string, string, error) {
+	names := []string{"consumer key", "consumer secret"}
+	if withPasskey {
+		names = append(names, "passkey")
+	}
+
+	reader := bufio.NewReader(in)
+	values := make([]string, 3)
+	for i, name := range names {
+		line, err := reader.ReadString('\n')
+		values[i] = strings.TrimSpace(line)
+		if values[i] == "" {
+			if err != nil {
+				return "", "", "", fmt.Errorf("--consumer-key-stdin expects the %s on line %d of stdin", name, i+1)
+			}
+			return "", "", "", fmt.Errorf("%s cannot be empty", name)
+		}
+	}
+
+	return values[0], values[1], values[2], nil
+}
+
 func init() {
 	rootCmd.AddCommand(loginCmd)
+	loginCmd.Flags().BoolVar(&withPasskey, "passkey", false, "Also store the Lipa Na M-Pesa Online (STK Push) passkey")
+	loginCmd.Flags().BoolVar(&consumerKeyStdin, "consumer-key-stdin", false, "Read the consumer key and secret (and passkey) from separate lines of stdin")
+	loginCmd.Flags().BoolVar(&loginFromEnv, "from-env", false, "Read the credentials from MPESA_CONSUMER_KEY and MPESA_CONSUMER_SECRET (and MPESA_PASSKEY)")
+	loginCmd.Flags().BoolVar(&skipVerify, "skip-verify", false, "Store the credentials without checking them against M-Pesa")
+	loginCmd.Flags().StringVar(&loginEnvironment, "env", "", "Environment to verify the credentials against: sandbox or production (default is the profile's environment)")
+	loginCmd.MarkFlagsMutuallyExclusive("consumer-key-stdin", "from-env")
 }
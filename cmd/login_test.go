@@ -74,3 +74,68 @@ func TestShowSpinnerStopsCleanly(t *testing.T) {
 	showSpinner("Testing spinner...", done)
 	// if it reaches here, spinner exited without hanging
 }
+
+// TestCredentialsFromReader tests reading credentials from stdin lines
+func TestCredentialsFromReader(t *testing.T) {
+	oldPasskey := withPasskey
+	defer func() { withPasskey = oldPasskey }()
+
+	withPasskey = false
+	key, secret, passkey, err := credentialsFromReader(strings.NewReader("my-key\nmy-secret\n"))
+	if err != nil || key != "my-key" || secret != "my-secret" || passkey != "" {
+		t.Errorf("unexpected credentials %q %q %q %v", key, secret, passkey, err)
+	}
+
+	// The last line does not need a newline
+	withPasskey = true
+	_, _, passkey, err = credentialsFromReader(strings.NewReader("my-key\r\nmy-secret\r\nmy-passkey"))
+	if err != nil || passkey != "my-passkey" {
+		t.Errorf("expected passkey from third line, got %q %v", passkey, err)
+	}
+
+	_, _, _, err = credentialsFromReader(strings.NewReader("my-key\nmy-secret\n"))
+	if err == nil || !strings.Contains(err.Error(), "passkey on line 3") {
+		t.Errorf("expected missing passkey error, got %v", err)
+	}
+
+	withPasskey = false
+	if _, _, _, err := credentialsFromReader(strings.NewReader("\nmy-secret\n")); err == nil {
+		t.Error("expected error for empty consumer key")
+	}
+}
+
+// TestCredentialsFromEnv tests reading credentials from the environment
+func TestCredentialsFromEnv(t *testing.T) {
+	oldPasskey := withPasskey
+	defer func() { withPasskey = oldPasskey }()
+	withPasskey = false
+
+	t.Setenv("MPESA_CONSUMER_KEY", "env-key")
+	t.Setenv("MPESA_CONSUMER_SECRET", "")
+	if _, _, _, err := credentialsFromEnv(); err == nil || !strings.Contains(err.Error(), "MPESA_CONSUMER_SECRET") {
+		t.Errorf("expected missing secret error, got %v", err)
+	}
+
+	t.Setenv("MPESA_CONSUMER_SECRET", "env-secret")
+	key, secret, _, err := credentialsFromEnv()
+	if err != nil || key != "env-key" || secret != "env-secret" {
+		t.Errorf("unexpected credentials %q %q %v", key, secret, err)
+	}
+
+	withPasskey = true
+	t.Setenv("MPESA_PASSKEY", "")
+	if _, _, _, err := credentialsFromEnv(); err == nil || !strings.Contains(err.Error(), "MPESA_PASSKEY") {
+		t.Errorf("expected missing passkey error, got %v", err)
+	}
+}
+
+// TestTargetEnvironment tests that --env is validated
+func TestTargetEnvironment(t *testing.T) {
+	if env, err := targetEnvironment("production"); err != nil || env != "production" {
+		t.Errorf("expected production, got %q %v", env, err)
+	}
+
+	if _, err := targetEnvironment("staging"); err == nil || !strings.Contains(err.Error(), "--env") {
+		t.Errorf("expected --env error, got %v", err)
+	}
+}
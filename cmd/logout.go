@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+
+	"github.com/spf13/cobra"
+)
+
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the stored credentials of a profile",
+	Long: `The logout command erases the consumer key, consumer secret, passkey and initiator
+password stored for the selected profile (see --profile), together with any access
+tokens cached for its consumer key and the record of when it was last verified.
+
+The profile's configuration is kept; run 'mpesa-cli login' to store new credentials.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile := activeProfile()
+		if err := mpesa.ValidateProfileName(profile); err != nil {
+			return err
+		}
+
+		status, err := mpesa.GetProfileCredentialStatus(profile)
+		if err != nil {
+			return err
+		}
+
+		// The consumer key is needed to find the cached tokens, so forget them first
+		if status.ConsumerKey != "" {
+			if err := forgetCachedTokens(status.ConsumerKey); err != nil {
+				return fmt.Errorf("failed to remove cached tokens: %w", err)
+			}
+		}
+
+		if err := mpesa.DeleteProfileCredentials(profile); err != nil {
+			return fmt.Errorf("failed to remove credentials: %w", err)
+		}
+
+		if path, err := mpesa.DefaultVerificationsPath(); err == nil {
+			_ = mpesa.NewVerificationStore(path).Delete(profile)
+		}
+
+		if status.ConsumerKey == "" && !status.ConsumerSecret && !status.Passkey && !status.InitiatorPassword {
+			fmt.Printf("No credentials were stored for profile '%s'.\n", profile)
+			return nil
+		}
+
+		fmt.Printf("✅ Logged out of profile '%s'; its credentials and cached tokens have been removed (backend: %s).\n", profile, credentialBackendName())
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logoutCmd)
+}
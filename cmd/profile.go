@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+var profileAddConfig mpesa.Config
+
+// profileCmd represents the profile parent command
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage named configuration profiles",
+	Long: `Profiles let you keep several M-Pesa apps, shortcodes and environments configured at once.
+Each profile has its own configuration block in the config file and its own credentials in the keychain.
+
+Select a profile with --profile, the MPESA_PROFILE environment variable or 'mpesa-cli profile use'.`,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured profiles",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names, err := mpesa.ListProfiles()
+		if err != nil {
+			return fmt.Errorf("error listing profiles: %w", err)
+		}
+
+		active := activeProfile()
+		for _, name := range names {
+			marker := " "
+			if name == active {
+				marker = "*"
+			}
+
+			config, err := mpesa.LoadProfileConfig(name)
+			if err != nil {
+				fmt.Printf("%s %s (invalid: %v)\n", marker, name, err)
+				continue
+			}
+			fmt.Printf("%s %s (%s, shortcode %s)\n", marker, name, config.Environment, valueOrNone(config.BusinessShortcode))
+		}
+
+		return nil
+	},
+}
+
+var profileAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a profile to the config file",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		exists, err := mpesa.ProfileExists(name)
+		if err != nil {
+			return fmt.Errorf("error reading profiles: %w", err)
+		}
+		if exists && name != mpesa.DefaultProfile {
+			return fmt.Errorf("profile '%s' already exists; remove it first", name)
+		}
+
+		if err := mpesa.SaveProfile(name, &profileAddConfig); err != nil {
+			return fmt.Errorf("error saving profile: %w", err)
+		}
+
+		fmt.Printf("✅ Profile '%s' added.\n", name)
+		fmt.Printf("💡 Tip: Run `mpesa-cli login --profile %s` to store its credentials.\n", name)
+		return nil
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Make a profile the default",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		if err := requireProfile(name); err != nil {
+			return err
+		}
+
+		if err := mpesa.SetDefaultProfile(name); err != nil {
+			return fmt.Errorf("error setting default profile: %w", err)
+		}
+
+		fmt.Printf("✅ Now using profile '%s'.\n", name)
+		return nil
+	},
+}
+
+var profileRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a profile and its stored credentials",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		if err := requireProfile(name); err != nil {
+			return err
+		}
+
+		if err := mpesa.RemoveProfile(name); err != nil {
+			return fmt.Errorf("error removing profile: %w", err)
+		}
+
+		if err := mpesa.DeleteProfileCredentials(name); err != nil {
+			return fmt.Errorf("profile removed but its credentials could not be deleted: %w", err)
+		}
+
+		fmt.Printf("✅ Profile '%s' removed.\n", name)
+		return nil
+	},
+}
+
+var profileShowCmd = &cobra.Command{
+	Use:   "show [name]",
+	Short: "Show the configuration of a profile",
+	Long:  `Show the configuration of the named profile, or of the active profile if no name is given.`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := activeProfile()
+		if len(args) == 1 {
+			name = args[0]
+		}
+
+		config, err := mpesa.LoadProfileConfig(name)
+		if err != nil {
+			return fmt.Errorf("error loading profile: %w", err)
+		}
+
+		credentials := "not stored"
+		if _, _, err := mpesa.GetProfileCredentials(name); err == nil {
+			credentials = "stored in " + credentialBackendName()
+		}
+
+		fmt.Printf("Profile: %s\n", config.Profile)
+		fmt.Println("--------------------")
+		fmt.Printf("Environment: %s\n", config.Environment)
+		fmt.Printf("Business Shortcode: %s\n", valueOrNone(config.BusinessShortcode))
+		fmt.Printf("Initiator: %s\n", valueOrNone(config.Initiator))
+		securityCredential := valueOrNone(maskSecret(config.SecurityCredential))
+		if _, err := mpesa.GetProfileInitiatorPassword(name); err == nil {
+			securityCredential = "generated from the stored initiator password"
+		}
+		fmt.Printf("Security Credential: %s\n", securityCredential)
+		fmt.Printf("Result URL: %s\n", valueOrNone(config.ResultURL))
+		fmt.Printf("Queue Timeout URL: %s\n", valueOrNone(config.QueueTimeOutURL))
+		fmt.Printf("Credentials: %s\n", credentials)
+		fmt.Println("--------------------")
+
+		return nil
+	},
+}
+
+// requireProfile returns an error if name is not a configured profile.
+func requireProfile(name string) error {
+	exists, err := mpesa.ProfileExists(name)
+	if err != nil {
+		return fmt.Errorf("error reading profiles: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("profile '%s' does not exist; see 'mpesa-cli profile list'", name)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+	profileCmd.AddCommand(profileListCmd, profileAddCmd, profileUseCmd, profileRemoveCmd, profileShowCmd)
+
+	profileAddCmd.Flags().StringVar(&profileAddConfig.Environment, "environment", "sandbox", "M-Pesa environment: sandbox or production")
+	profileAddCmd.Flags().StringVar(&profileAddConfig.BusinessShortcode, "shortcode", "", "Business shortcode (Paybill or Buygoods)")
+	profileAddCmd.Flags().StringVar(&profileAddConfig.SecurityCredential, "security-credential", "", "Encrypted security credential of the initiator")
+	profileAddCmd.Flags().StringVar(&profileAddConfig.Initiator, "initiator", "", "API initiator name")
+	profileAddCmd.Flags().StringVar(&profileAddConfig.ResultURL, "result-url", "", "URL that receives transaction results")
+	profileAddCmd.Flags().StringVar(&profileAddConfig.QueueTimeOutURL, "queue-timeout-url", "", "URL that receives queue timeout notifications")
+}
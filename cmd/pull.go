@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// pullCmd represents the pull parent command
+var pullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Retrieve historical C2B transactions",
+	Long: `Parent command for the Pull Transactions API, which returns the C2B transactions of
+the business shortcode of the selected profile for a period, e.g. to backfill
+payments the confirmation URL missed.
+
+Register the shortcode once with 'mpesa-cli pull register'.`,
+}
+
+func init() {
+	rootCmd.AddCommand(pullCmd)
+}
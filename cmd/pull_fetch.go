@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pullFrom   string
+	pullTo     string
+	pullFormat string
+	pullOut    string
+)
+
+// pullDateLayouts are the accepted formats of --from and --to, in East Africa Time
+var pullDateLayouts = []string{"2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
+
+// pullCSVHeader is the header row of CSV output
+var pullCSVHeader = []string{"transaction_id", "date", "msisdn", "sender", "type", "bill_reference", "amount", "organization"}
+
+var pullFetchCmd = &cobra.Command{
+	Use:   "fetch",
+	Short: "Export the C2B transactions of a period",
+	Long: `Export the C2B transactions of the business shortcode between --from and --to,
+paging through the results until all of them are retrieved.
+
+Dates are in East Africa Time as YYYY-MM-DD or "YYYY-MM-DD HH:MM:SS"; a date
+without a time means midnight. Transactions are written as JSON lines (one object
+per line) or CSV to standard output, or to --out.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, err := parsePullDate(pullFrom)
+		if err != nil {
+			return fmt.Errorf("invalid --from: %w", err)
+		}
+		to, err := parsePullDate(pullTo)
+		if err != nil {
+			return fmt.Errorf("invalid --to: %w", err)
+		}
+
+		if pullFormat != "jsonl" && pullFormat != "csv" {
+			return fmt.Errorf("--format must be jsonl or csv, got: %s", pullFormat)
+		}
+
+		client, err := profileClient()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "❌ Authentication failed.")
+			return err
+		}
+
+		var write func([]mpesa.PullTransaction) error
+		var flush func() error
+
+		// --out is written to a temporary file that replaces it only once the export
+		// is complete, so a failed export leaves a previous one intact
+		out := io.Writer(os.Stdout)
+		var file *os.File
+		if pullOut != "" {
+			file, err = os.CreateTemp(filepath.Dir(pullOut), "."+filepath.Base(pullOut)+"-*")
+			if err != nil {
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+			defer func() {
+				_ = file.Close()
+				_ = os.Remove(file.Name())
+			}()
+			out = file
+		}
+
+		switch pullFormat {
+		case "jsonl":
+			encoder := json.NewEncoder(out)
+			write = func(page []mpesa.PullTransaction) error {
+				for _, trx := range page {
+					if err := encoder.Encode(trx); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			flush = func() error { return nil }
+		case "csv":
+			writer := csv.NewWriter(out)
+			if err := writer.Write(pullCSVHeader); err != nil {
+				return err
+			}
+			write = func(page []mpesa.PullTransaction) error {
+				for _, trx := range page {
+					if err := writer.Write([]string{trx.TransactionID, trx.TrxDate, trx.MSISDN, trx.Sender, trx.TransactionType, trx.BillReference, trx.Amount, trx.OrganizationName}); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			flush = func() error {
+				writer.Flush()
+				return writer.Error()
+			}
+		}
+
+		count := 0
+		err = client.PullTransactions(from, to, func(page []mpesa.PullTransaction) error {
+			count += len(page)
+			fmt.Fprintf(os.Stderr, "\rFetched %d transactions...", count)
+			return write(page)
+		})
+		fmt.Fprintln(os.Stderr)
+
+		if flushErr := flush(); err == nil {
+			err = flushErr
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Export stopped after %d transactions.\n", count)
+			return fmt.Errorf("error pulling transactions: %w", err)
+		}
+
+		if file != nil {
+			if err := file.Close(); err != nil {
+				return fmt.Errorf("failed to write output file: %w", err)
+			}
+			if err := os.Rename(file.Name(), pullOut); err != nil {
+				return fmt.Errorf("failed to write output file: %w", err)
+			}
+		}
+
+		fmt.Fprintf(os.Stderr, "✔ Exported %d transactions.\n", count)
+		return nil
+	},
+}
+
+// parsePullDate parses a --from or --to value in East Africa Time.
+func parsePullDate(value string) (time.Time, error) {
+	for _, layout := range pullDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, mpesa.EAT); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("'%s' is not a date like 2024-07-01 or \"2024-07-01 08:30:00\"", value)
+}
+
+func init() {
+	pullCmd.AddCommand(pullFetchCmd)
+	pullFetchCmd.Flags().StringVar(&pullFrom, "from", "", "Start of the period (required)")
+	pullFetchCmd.Flags().StringVar(&pullTo, "to", "", "End of the period (required)")
+	pullFetchCmd.Flags().StringVar(&pullFormat, "format", "jsonl", "Output format: jsonl or csv")
+	pullFetchCmd.Flags().StringVarP(&pullOut, "out", "o", "", "Write transactions to this file instead of standard output")
+	_ = pullFetchCmd.MarkFlagRequired("from")
+	_ = pullFetchCmd.MarkFlagRequired("to")
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+var pullRegisterOptions mpesa.PullRegisterOptions
+
+var pullRegisterCmd = &cobra.Command{
+	Use:   "register",
+	Short: "Register the business shortcode for pull transactions",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		done := make(chan bool)
+		go showSpinner("Registering for pull transactions...", done)
+
+		client, err := profileClient()
+		if err != nil {
+			done <- true
+			<-done
+			fmt.Println("\n❌ Authentication failed.")
+			return err
+		}
+
+		result, err := client.RegisterPull(pullRegisterOptions)
+		done <- true
+		<-done
+
+		if err != nil {
+			fmt.Println("\n❌ Registration failed.")
+			return fmt.Errorf("error registering for pull transactions: %w", err)
+		}
+
+		fmt.Println("\n✔ Registered for pull transactions!")
+		fmt.Println("--------------------")
+		fmt.Printf("Status: %s\n", result.ResponseStatus)
+		fmt.Printf("Description: %s\n", result.ResponseDescription)
+		fmt.Printf("Reference: %s\n", result.ResponseRefID)
+		fmt.Println("--------------------")
+
+		return nil
+	},
+}
+
+func init() {
+	pullCmd.AddCommand(pullRegisterCmd)
+	pullRegisterCmd.Flags().StringVar(&pullRegisterOptions.CallbackURL, "callback", "", "URL that receives pull transaction notifications (required)")
+	pullRegisterCmd.Flags().StringVar(&pullRegisterOptions.NominatedNumber, "nominated-number", "", "Phone number that receives registration notifications (required)")
+	_ = pullRegisterCmd.MarkFlagRequired("callback")
+	_ = pullRegisterCmd.MarkFlagRequired("nominated-number")
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+)
+
+// TestParsePullDate tests the accepted --from and --to formats
+func TestParsePullDate(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{value: "2024-07-01", expected: "2024-06-30T21:00:00Z"},
+		{value: "2024-07-01 08:30:00", expected: "2024-07-01T05:30:00Z"},
+		{value: "2024-07-01T08:30:00", expected: "2024-07-01T05:30:00Z"},
+	}
+
+	for _, tt := range tests {
+		got, err := parsePullDate(tt.value)
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", tt.value, err)
+			continue
+		}
+		if got.UTC().Format(time.RFC3339) != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.value, tt.expected, got.UTC().Format(time.RFC3339))
+		}
+	}
+
+	if _, err := parsePullDate("01/07/2024"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+// TestPullFetchKeepsPreviousExport tests that --out is not touched when the export fails
+func TestPullFetchKeepsPreviousExport(t *testing.T) {
+	oldFrom, oldTo, oldFormat, oldOut := pullFrom, pullTo, pullFormat, pullOut
+	defer func() { pullFrom, pullTo, pullFormat, pullOut = oldFrom, oldTo, oldFormat, oldOut }()
+
+	mpesa.SetCredentialStore(mpesa.NewMemoryStore())
+	defer mpesa.SetCredentialStore(nil)
+
+	dir := t.TempDir()
+	pullOut = filepath.Join(dir, "export.jsonl")
+	if err := os.WriteFile(pullOut, []byte("previous export\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	pullFrom, pullTo, pullFormat = "2024-07-01", "2024-07-02", "jsonl"
+
+	if err := pullFetchCmd.RunE(pullFetchCmd, nil); err == nil {
+		t.Fatal("expected error without credentials")
+	}
+
+	data, err := os.ReadFile(pullOut)
+	if err != nil || string(data) != "previous export\n" {
+		t.Errorf("expected previous export to be kept, got %q %v", data, err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("expected no temporary files to be left behind, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// qrCmd represents the qr parent command
+var qrCmd = &cobra.Command{
+	Use:   "qr",
+	Short: "M-Pesa Dynamic QR codes",
+	Long:  `Parent command for generating QR codes that customers scan with the M-Pesa app to pay.`,
+}
+
+func init() {
+	rootCmd.AddCommand(qrCmd)
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+var (
+	qrOptions mpesa.QROptions
+	qrOut     string
+)
+
+var qrCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a Dynamic QR code for a payment",
+	Long: `Create a Dynamic QR code that customers scan with the M-Pesa app to pay.
+
+The QR code image returned by M-Pesa is written to --out, or to a temporary file
+whose path is printed. The file format follows the extension of --out: .png writes
+the image as is, .svg wraps it in a vector image, e.g. for web pages.
+
+Transaction codes (--trx-code):
+  BG  Buy Goods till (default CPI is the business shortcode)
+  PB  Paybill (default CPI is the business shortcode)
+  WA  Withdraw cash at an agent
+  SM  Send money to a phone number
+  SB  Send to a business`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		qrOptions.TrxCode = strings.ToUpper(qrOptions.TrxCode)
+
+		format := strings.ToLower(filepath.Ext(qrOut))
+		if qrOut != "" && format != ".png" && format != ".svg" {
+			return fmt.Errorf("--out must end in .png or .svg, got: %s", qrOut)
+		}
+
+		done := make(chan bool)
+		go showSpinner("Generating QR code...", done)
+
+		client, err := profileClient()
+		if err != nil {
+			done <- true
+			<-done
+			fmt.Println("\n❌ Authentication failed.")
+			return err
+		}
+
+		result, err := client.GenerateQR(qrOptions)
+		done <- true
+		<-done
+
+		if err != nil {
+			fmt.Println("\n❌ QR code generation failed.")
+			return fmt.Errorf("error generating QR code: %w", err)
+		}
+
+		pngData, err := result.PNG()
+		if err != nil {
+			return err
+		}
+
+		path, err := writeQRCode(qrOut, pngData)
+		if err != nil {
+			return fmt.Errorf("failed to write QR code: %w", err)
+		}
+
+		fmt.Printf("\n✅ QR code written to %s\n", path)
+		fmt.Printf("%s: KES %d (ref %s)\n", qrOptions.MerchantName, qrOptions.Amount, qrOptions.RefNo)
+		if qrOut == "" {
+			fmt.Println("💡 Tip: Use --out to choose where the image is written.")
+		}
+
+		return nil
+	},
+}
+
+// writeQRCode writes the QR code image to path as PNG or SVG by its extension, or to
+// a new temporary PNG file if path is empty, and returns the path written.
+func writeQRCode(path string, pngData []byte) (string, error) {
+	if path == "" {
+		file, err := os.CreateTemp("", "mpesa-qr-*.png")
+		if err != nil {
+			return "", err
+		}
+		defer func() { _ = file.Close() }()
+		if _, err := file.Write(pngData); err != nil {
+			return "", err
+		}
+		return file.Name(), file.Close()
+	}
+
+	data := pngData
+	if strings.EqualFold(filepath.Ext(path), ".svg") {
+		svg, err := qrSVG(pngData)
+		if err != nil {
+			return "", err
+		}
+		data = []byte(svg)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil { // #nosec G306 - the QR code is not secret
+		return "", err
+	}
+	return path, nil
+}
+
+// qrSVG wraps the PNG image of a QR code in an SVG document of the same size. The
+// image is embedded unchanged, so the code scans exactly like the one M-Pesa made.
+func qrSVG(pngData []byte) (string, error) {
+	config, err := png.DecodeConfig(bytes.NewReader(pngData))
+	if err != nil {
+		return "", fmt.Errorf("QR code is not a PNG image: %w", err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		config.Width, config.Height, config.Width, config.Height)
+	fmt.Fprintf(&b, `<image width="%d" height="%d" style="image-rendering:pixelated" href="data:image/png;base64,%s"/>`+"\n",
+		config.Width, config.Height, base64.StdEncoding.EncodeToString(pngData))
+	b.WriteString("</svg>\n")
+	return b.String(), nil
+}
+
+func init() {
+	qrCmd.AddCommand(qrCreateCmd)
+	qrCreateCmd.Flags().StringVar(&qrOptions.MerchantName, "merchant-name", "", "Business name shown to the customer (required)")
+	qrCreateCmd.Flags().StringVar(&qrOptions.RefNo, "ref", "", "Transaction reference (required)")
+	qrCreateCmd.Flags().IntVar(&qrOptions.Amount, "amount", 0, "Amount to pay in KES (required)")
+	qrCreateCmd.Flags().StringVar(&qrOptions.TrxCode, "trx-code", mpesa.QRBuyGoods, "Transaction code: BG, WA, PB, SM or SB")
+	qrCreateCmd.Flags().StringVar(&qrOptions.CPI, "cpi", "", "Credit party identifier: till, Paybill, agent or phone number")
+	qrCreateCmd.Flags().StringVarP(&qrOut, "out", "o", "", "Write the QR code to a .png or .svg file instead of the terminal")
+	_ = qrCreateCmd.MarkFlagRequired("merchant-name")
+	_ = qrCreateCmd.MarkFlagRequired("ref")
+	_ = qrCreateCmd.MarkFlagRequired("amount")
+}
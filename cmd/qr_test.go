@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testPNG returns a small PNG image standing in for a QR code from the API
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 300, 300))); err != nil {
+		t.Fatalf("failed to encode PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// TestQRSVG tests that the PNG is embedded unchanged at its own size
+func TestQRSVG(t *testing.T) {
+	pngData := testPNG(t)
+
+	svg, err := qrSVG(pngData)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(svg, "<svg") || !strings.HasSuffix(svg, "</svg>\n") {
+		t.Errorf("expected an SVG document, got %q", svg)
+	}
+	if !strings.Contains(svg, `width="300" height="300"`) || !strings.Contains(svg, base64.StdEncoding.EncodeToString(pngData)) {
+		t.Errorf("expected the PNG embedded at 300x300, got %q", svg)
+	}
+
+	if _, err := qrSVG([]byte("not a png")); err == nil {
+		t.Error("expected error for invalid image")
+	}
+}
+
+// TestWriteQRCode tests writing the API image as PNG, as SVG and to a temporary file
+func TestWriteQRCode(t *testing.T) {
+	pngData := testPNG(t)
+	dir := t.TempDir()
+
+	path, err := writeQRCode(filepath.Join(dir, "qr.png"), pngData)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); !bytes.Equal(data, pngData) {
+		t.Error("expected the PNG to be written unchanged")
+	}
+
+	path, err = writeQRCode(filepath.Join(dir, "qr.svg"), pngData)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); !bytes.HasPrefix(data, []byte("<svg")) {
+		t.Errorf("expected an SVG file, got %q", data)
+	}
+
+	path, err = writeQRCode("", pngData)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer func() { _ = os.Remove(path) }()
+	if data, _ := os.ReadFile(path); filepath.Ext(path) != ".png" || !bytes.Equal(data, pngData) {
+		t.Errorf("expected the PNG in a temporary file, got %s", path)
+	}
+}
@@ -2,38 +2,89 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/martwebber/mpesa-cli/pkg/mpesa"
 	"github.com/spf13/cobra"
 )
 
-var transactionID string
+var (
+	queryOptions        mpesa.TransactionStatusOptions
+	queryIdentifierType string
+	queryWait           bool
+)
+
+// identifierTypes maps the --identifier-type flag values to Transaction Status identifier types
+var identifierTypes = map[string]string{
+	"msisdn":    mpesa.IdentifierMSISDN,
+	"till":      mpesa.IdentifierTill,
+	"shortcode": mpesa.IdentifierShortcode,
+}
 
 var queryCmd = &cobra.Command{
 	Use:   "query",
 	Short: "Query the status of an M-Pesa transaction",
-	Long:  `Query the status of a specific M-Pesa transaction by providing its ID.`,
+	Long: `Query the status of a specific M-Pesa transaction by providing its ID.
+
+A transaction whose receipt number you never got can be looked up by the
+OriginatorConversationID of the request that made it with --original-conversation-id.
+By default the transaction is looked up for the business shortcode; use
+--identifier-type and --party-a to look it up for a till or phone number instead.
+
+M-Pesa delivers the status to result_url. To see it here, use --wait with a temporary
+listener at --listen that is exposed to the internet (e.g. with a tunnel) at --public-url;
+the receipt, amount, parties and completion time are then printed.
+
+Exit codes:
+  0  status received (or query accepted, without --wait)
+  1  error
+  4  the query failed
+  5  no result received within --timeout`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		done := make(chan bool)
-		go showSpinner(fmt.Sprintf("Querying status for transaction ID: %s", transactionID), done)
+		identifierType, ok := identifierTypes[queryIdentifierType]
+		if !ok {
+			return fmt.Errorf("--identifier-type must be msisdn, till or shortcode, got: %s", queryIdentifierType)
+		}
+		queryOptions.IdentifierType = identifierType
 
-		consumerKey, consumerSecret, err := mpesa.GetCredentials()
+		if queryWait && listenAddr == "" {
+			return fmt.Errorf("--wait needs --listen and --public-url to receive the result")
+		}
+		if !queryWait && listenAddr != "" {
+			return fmt.Errorf("--listen is only used with --wait; add --wait to wait for the status")
+		}
+
+		lookup := queryOptions.TransactionID
+		if lookup == "" {
+			lookup = queryOptions.OriginalConversationID
+		}
+
+		config, err := loadConfig()
 		if err != nil {
-			done <- true
-			<-done
-			fmt.Println("\n❌ Failed to get credentials.")
-			return fmt.Errorf("error getting credentials: %w", err)
+			fmt.Println("❌ Failed to load configuration.")
+			return fmt.Errorf("error loading config: %w", err)
+		}
+
+		listener, err := startResultListener(config)
+		if err != nil {
+			return err
 		}
+		if listener != nil {
+			defer func() { _ = listener.Close() }()
+		}
+
+		done := make(chan bool)
+		go showSpinner(fmt.Sprintf("Querying status for transaction ID: %s", lookup), done)
 
-		accessToken, err := mpesa.GetAccessToken(consumerKey, consumerSecret)
+		client, err := configClient(config)
 		if err != nil {
 			done <- true
 			<-done
 			fmt.Println("\n❌ Authentication failed.")
-			return fmt.Errorf("error getting access token: %w", err)
+			return err
 		}
 
-		status, err := mpesa.QueryTransaction(accessToken, transactionID)
+		status, err := client.QueryTransactionStatus(queryOptions)
 		done <- true
 		<-done
 
@@ -49,12 +100,66 @@ var queryCmd = &cobra.Command{
 		fmt.Printf("Conversation ID: %s\n", status.ConversationID)
 		fmt.Println("--------------------")
 
-		return nil
+		if listener == nil {
+			fmt.Printf("💡 Tip: The status will be sent to %s. Use --wait with --listen and --public-url to wait for it here.\n", config.ResultURL)
+			return nil
+		}
+
+		result, err := waitForResult(cmd, listener, status.ConversationID, status.OriginatorConversationID)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("✅ Transaction status received!")
+		return printTransactionStatus(result)
 	},
 }
 
+// printTransactionStatus prints the details of a Transaction Status result.
+func printTransactionStatus(result *mpesa.Result) error {
+	status, err := result.TransactionStatus()
+	if err != nil {
+		return err
+	}
+
+	// A non-numeric amount is not decoded but kept with the other unknown parameters
+	amount := status.Amount.String()
+	if amount == "" {
+		amount, _ = status.Extra.Get("Amount")
+	}
+
+	fmt.Println("--------------------")
+	fmt.Printf("Result: %s\n", result.ResultDesc)
+	fmt.Printf("Receipt: %s\n", valueOrNone(status.ReceiptNo))
+	fmt.Printf("Status: %s\n", valueOrNone(status.TransactionStatus))
+	fmt.Printf("Amount: %s\n", valueOrNone(amount))
+	fmt.Printf("Debit Party: %s\n", valueOrNone(status.DebitPartyName))
+	fmt.Printf("Credit Party: %s\n", valueOrNone(status.CreditPartyName))
+	fmt.Printf("Initiated: %s\n", valueOrNone(formatResultTime(status.InitiatedTime)))
+	fmt.Printf("Completed: %s\n", valueOrNone(formatResultTime(status.FinalisedTime)))
+	fmt.Println("--------------------")
+	return nil
+}
+
+// formatResultTime formats a YYYYMMDDHHmmss result timestamp for display.
+// Values in any other format are returned unchanged.
+func formatResultTime(value string) string {
+	t, err := time.Parse("20060102150405", value)
+	if err != nil {
+		return value
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
 func init() {
 	transactionsCmd.AddCommand(queryCmd)
-	queryCmd.Flags().StringVarP(&transactionID, "id", "i", "", "The ID of the transaction to query (required)")
-	_ = queryCmd.MarkFlagRequired("id")
+	queryCmd.Flags().StringVarP(&queryOptions.TransactionID, "id", "i", "", "The ID of the transaction to query")
+	queryCmd.Flags().StringVar(&queryOptions.OriginalConversationID, "original-conversation-id", "", "OriginatorConversationID of the request that made the transaction")
+	queryCmd.Flags().StringVar(&queryIdentifierType, "identifier-type", "shortcode", "Type of --party-a: msisdn, till or shortcode")
+	queryCmd.Flags().StringVar(&queryOptions.PartyA, "party-a", "", "Party that made or received the transaction (default is the business shortcode)")
+	queryCmd.Flags().StringVar(&queryOptions.Remarks, "remarks", "Status Check", "Remarks sent with the query, 2 to 100 characters")
+	queryCmd.Flags().StringVar(&queryOptions.Occasion, "occasion", "Verification", "Occasion sent with the query, up to 100 characters")
+	queryCmd.Flags().BoolVar(&queryWait, "wait", false, "Wait for the transaction status on the --listen listener")
+	addListenFlags(queryCmd)
+	queryCmd.MarkFlagsOneRequired("id", "original-conversation-id")
 }
@@ -193,3 +193,49 @@ func TestQueryCommandWithRealQueryCmd(t *testing.T) {
 		t.Error("expected RunE to be set")
 	}
 }
+
+// TestQueryRejectsUnknownIdentifierType tests that --identifier-type is validated before any API call
+func TestQueryRejectsUnknownIdentifierType(t *testing.T) {
+	oldType := queryIdentifierType
+	defer func() { queryIdentifierType = oldType }()
+
+	queryIdentifierType = "agent"
+	err := queryCmd.RunE(queryCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "--identifier-type") {
+		t.Errorf("expected identifier type error, got %v", err)
+	}
+}
+
+// TestQueryWaitRequiresListen tests that --wait is refused without a listener
+func TestQueryWaitRequiresListen(t *testing.T) {
+	oldWait, oldAddr := queryWait, listenAddr
+	defer func() { queryWait, listenAddr = oldWait, oldAddr }()
+
+	queryWait, listenAddr = true, ""
+	err := queryCmd.RunE(queryCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "--listen") {
+		t.Errorf("expected --listen error, got %v", err)
+	}
+}
+
+// TestFormatResultTime tests formatting of result timestamps
+func TestFormatResultTime(t *testing.T) {
+	if got := formatResultTime("20240115143000"); got != "2024-01-15 14:30:00" {
+		t.Errorf("unexpected time %q", got)
+	}
+	if got := formatResultTime("soon"); got != "soon" {
+		t.Errorf("expected unparseable value unchanged, got %q", got)
+	}
+}
+
+// TestQueryListenRequiresWait tests that --listen is refused without --wait
+func TestQueryListenRequiresWait(t *testing.T) {
+	oldWait, oldAddr := queryWait, listenAddr
+	defer func() { queryWait, listenAddr = oldWait, oldAddr }()
+
+	queryWait, listenAddr = false, "127.0.0.1:0"
+	err := queryCmd.RunE(queryCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "--wait") {
+		t.Errorf("expected --wait error, got %v", err)
+	}
+}
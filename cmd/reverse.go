@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+var reverseOptions mpesa.ReversalOptions
+
+// transactionDetailKeys are the Transaction Status result parameters shown before a reversal
+var transactionDetailKeys = []string{"ReceiptNo", "Amount", "TransactionStatus", "DebitPartyName", "CreditPartyName", "InitiatedTime", "FinalisedTime"}
+
+var reverseCmd = &cobra.Command{
+	Use:   "reverse",
+	Short: "Reverse a completed M-Pesa transaction",
+	Long: `Reverse a completed transaction received by the business shortcode.
+
+Before anything is reversed, the transaction is looked up with a Transaction Status
+query and its details are shown. The reversal is refused if the reported amount
+differs from --amount. Both results are delivered asynchronously, so a temporary
+listener is required: run it with --listen and expose it at --public-url.
+
+In production you must type the transaction ID to confirm the reversal.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if listenAddr == "" {
+			return fmt.Errorf("reversals need --listen and --public-url to check the transaction before reversing it")
+		}
+
+		config, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("error loading config: %w", err)
+		}
+
+		listener, err := startResultListener(config)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = listener.Close() }()
+
+		done := make(chan bool)
+		go showSpinner(fmt.Sprintf("Looking up transaction %s", reverseOptions.TransactionID), done)
+
+		client, err := configClient(config)
+		if err != nil {
+			done <- true
+			<-done
+			fmt.Println("\n❌ Authentication failed.")
+			return err
+		}
+
+		status, err := client.QueryTransactionStatus(reverseStatusOptions(reverseOptions))
+		done <- true
+		<-done
+
+		if err != nil {
+			fmt.Println("\n❌ Query failed.")
+			return fmt.Errorf("error querying transaction: %w", err)
+		}
+
+		details, err := waitForResult(cmd, listener, status.ConversationID, status.OriginatorConversationID)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("\nTransaction %s\n", reverseOptions.TransactionID)
+		fmt.Println("--------------------")
+		for _, key := range transactionDetailKeys {
+			if value, ok := details.Parameter(key); ok {
+				fmt.Printf("%s: %s\n", key, value)
+			}
+		}
+		fmt.Println("--------------------")
+
+		reported, _ := details.Parameter("Amount")
+		if !amountsMatch(reported, reverseOptions.Amount) {
+			fmt.Println("❌ Amounts disagree.")
+			return fmt.Errorf("refusing to reverse: transaction amount is %s but --amount is %d", valueOrNone(reported), reverseOptions.Amount)
+		}
+
+		summary := fmt.Sprintf("reverse KES %d of transaction %s", reverseOptions.Amount, reverseOptions.TransactionID)
+		if err := confirmTypedProduction(config, summary, reverseOptions.TransactionID); err != nil {
+			return err
+		}
+
+		done = make(chan bool)
+		go showSpinner(fmt.Sprintf("Reversing transaction %s", reverseOptions.TransactionID), done)
+
+		ack, err := client.ReverseTransaction(reverseOptions)
+		done <- true
+		<-done
+
+		if err != nil {
+			fmt.Println("\n❌ Reversal failed.")
+			return fmt.Errorf("error reversing transaction: %w", err)
+		}
+
+		fmt.Println("\n✔ Reversal accepted!")
+		fmt.Printf("Conversation ID: %s\n", ack.ConversationID)
+
+		result, err := waitForResult(cmd, listener, ack.ConversationID, ack.OriginatorConversationID)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("✅ Transaction reversed.")
+		fmt.Printf("Result: %s\n", result.ResultDesc)
+		fmt.Printf("Reversal Transaction ID: %s\n", valueOrNone(result.TransactionID))
+
+		return nil
+	},
+}
+
+// reverseStatusOptions returns the Transaction Status query that looks up the transaction
+// to be reversed, as seen by the party that received it.
+func reverseStatusOptions(opts mpesa.ReversalOptions) mpesa.TransactionStatusOptions {
+	status := mpesa.TransactionStatusOptions{TransactionID: opts.TransactionID}
+	if opts.Receiver != "" {
+		status.PartyA = opts.Receiver
+		status.IdentifierType = mpesa.IdentifierShortcode
+	}
+	return status
+}
+
+// amountsMatch reports whether the amount in a Transaction Status result equals amount.
+// M-Pesa reports amounts as decimals, e.g. "100.00".
+func amountsMatch(reported string, amount int) bool {
+	value, err := strconv.ParseFloat(reported, 64)
+	if err != nil {
+		return false
+	}
+	return value == float64(amount)
+}
+
+func init() {
+	transactionsCmd.AddCommand(reverseCmd)
+	reverseCmd.Flags().StringVar(&reverseOptions.TransactionID, "id", "", "The ID of the transaction to reverse (required)")
+	reverseCmd.Flags().IntVar(&reverseOptions.Amount, "amount", 0, "Amount of the transaction in KES (required)")
+	reverseCmd.Flags().StringVar(&reverseOptions.Receiver, "receiver", "", "Shortcode that received the transaction (default is the business shortcode)")
+	reverseCmd.Flags().StringVar(&reverseOptions.Remarks, "remarks", "Reversal", "Remarks sent with the reversal, 2 to 100 characters")
+	reverseCmd.Flags().StringVar(&reverseOptions.Occasion, "occasion", "", "Optional occasion sent with the reversal")
+	addListenFlags(reverseCmd)
+	_ = reverseCmd.MarkFlagRequired("id")
+	_ = reverseCmd.MarkFlagRequired("amount")
+}
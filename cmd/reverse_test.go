@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+)
+
+// TestAmountsMatch tests comparing reported decimal amounts with --amount
+func TestAmountsMatch(t *testing.T) {
+	tests := []struct {
+		reported string
+		amount   int
+		expected bool
+	}{
+		{reported: "100.00", amount: 100, expected: true},
+		{reported: "100", amount: 100, expected: true},
+		{reported: "100.50", amount: 100, expected: false},
+		{reported: "99.00", amount: 100, expected: false},
+		{reported: "", amount: 100, expected: false},
+	}
+
+	for _, tt := range tests {
+		if got := amountsMatch(tt.reported, tt.amount); got != tt.expected {
+			t.Errorf("amountsMatch(%q, %d): expected %v, got %v", tt.reported, tt.amount, tt.expected, got)
+		}
+	}
+}
+
+// TestReverseRequiresListener tests that nothing is sent without a listener for the pre-flight check
+func TestReverseRequiresListener(t *testing.T) {
+	listenAddr = ""
+	err := reverseCmd.RunE(reverseCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "--listen") {
+		t.Errorf("expected --listen to be required, got %v", err)
+	}
+}
+
+// TestReverseStatusOptions tests that the pre-flight query is made as the receiver of the transaction
+func TestReverseStatusOptions(t *testing.T) {
+	status := reverseStatusOptions(mpesa.ReversalOptions{TransactionID: "OEI2AK4Q16", Receiver: "600000"})
+	if status.TransactionID != "OEI2AK4Q16" || status.PartyA != "600000" || status.IdentifierType != mpesa.IdentifierShortcode {
+		t.Errorf("expected query as receiver 600000, got %+v", status)
+	}
+
+	// Without --receiver the client defaults PartyA to the business shortcode
+	status = reverseStatusOptions(mpesa.ReversalOptions{TransactionID: "OEI2AK4Q16"})
+	if status.PartyA != "" || status.IdentifierType != "" {
+		t.Errorf("expected default party, got %+v", status)
+	}
+}
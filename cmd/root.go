@@ -4,6 +4,7 @@ Copyright © 2025 NAME HERE mwangi.martin24@gmail.com
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -11,7 +12,10 @@ import (
 	"github.com/spf13/viper"
 )
 
-var cfgFile string
+var (
+	cfgFile     string
+	profileName string
+)
 
 // Version information
 var (
@@ -38,6 +42,7 @@ for various operations like transaction queries, authentication, and health chec
 Features:
 - Secure credential management using system keychain
 - Support for both sandbox and production environments
+- Named profiles for multiple apps, shortcodes and environments
 - Transaction status queries
 - Health checks and diagnostics
 - Easy authentication workflow
@@ -48,6 +53,10 @@ Get started by running: mpesa-cli login`,
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
@@ -56,6 +65,7 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.mpesa-cli.yaml)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "profile to use (default is $MPESA_PROFILE, default_profile or \"default\")")
 
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// standingOrderCmd represents the standing-order parent command
+var standingOrderCmd = &cobra.Command{
+	Use:   "standing-order",
+	Short: "M-Pesa Ratiba standing orders",
+	Long: `Parent command for M-Pesa Ratiba standing orders: recurring payments from customers
+to the business shortcode of the selected profile.
+
+Standing order requests are signed with the passkey of the selected profile.
+Store it with: mpesa-cli login --passkey`,
+}
+
+func init() {
+	rootCmd.AddCommand(standingOrderCmd)
+}
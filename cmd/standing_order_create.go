@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+var (
+	standingOrderOptions   mpesa.StandingOrderOptions
+	standingOrderFrequency string
+	standingOrderStart     string
+	standingOrderEnd       string
+	standingOrderType      string
+)
+
+// standingOrderFrequencies maps the names accepted by --frequency to Ratiba frequency codes
+var standingOrderFrequencies = map[string]int{
+	"one-off":     mpesa.FrequencyOneOff,
+	"daily":       mpesa.FrequencyDaily,
+	"weekly":      mpesa.FrequencyWeekly,
+	"monthly":     mpesa.FrequencyMonthly,
+	"bi-monthly":  mpesa.FrequencyBiMonthly,
+	"quarterly":   mpesa.FrequencyQuarterly,
+	"half-yearly": mpesa.FrequencyHalfYearly,
+	"yearly":      mpesa.FrequencyYearly,
+}
+
+// standingOrderTypes maps the --type flag values to standing order transaction types
+var standingOrderTypes = map[string]string{
+	"paybill":  mpesa.StandingOrderPayBill,
+	"buygoods": mpesa.StandingOrderPayMerchant,
+}
+
+var standingOrderCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a standing order for a customer",
+	Long: `Create a Ratiba standing order that pays the business shortcode at a fixed frequency
+between --start and --end. The customer is asked to approve it on their phone.
+
+Frequencies (--frequency, by code or name):
+  1 one-off   2 daily       3 weekly       4 monthly
+  5 bi-monthly 6 quarterly  7 half-yearly  8 yearly
+
+Dates are given as YYYY-MM-DD. The start date may not be in the past and the end
+date must be after it.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		frequency, err := parseFrequency(standingOrderFrequency)
+		if err != nil {
+			return err
+		}
+		standingOrderOptions.Frequency = frequency
+
+		transactionType, ok := standingOrderTypes[standingOrderType]
+		if !ok {
+			return fmt.Errorf("--type must be paybill or buygoods, got: %s", standingOrderType)
+		}
+		standingOrderOptions.TransactionType = transactionType
+
+		if standingOrderOptions.StartDate, err = time.Parse("2006-01-02", standingOrderStart); err != nil {
+			return fmt.Errorf("invalid --start '%s': use YYYY-MM-DD", standingOrderStart)
+		}
+		if standingOrderOptions.EndDate, err = time.Parse("2006-01-02", standingOrderEnd); err != nil {
+			return fmt.Errorf("invalid --end '%s': use YYYY-MM-DD", standingOrderEnd)
+		}
+
+		if standingOrderOptions.AccountReference == "" {
+			standingOrderOptions.AccountReference = standingOrderOptions.Name
+		}
+
+		passkey, err := mpesa.GetProfilePasskey(activeProfile())
+		if err != nil {
+			fmt.Println("❌ Failed to get passkey.")
+			return fmt.Errorf("error getting passkey: %w", err)
+		}
+
+		done := make(chan bool)
+		go showSpinner(fmt.Sprintf("Creating standing order for %s", standingOrderOptions.PhoneNumber), done)
+
+		client, err := profileClient(mpesa.WithPasskey(passkey))
+		if err != nil {
+			done <- true
+			<-done
+			fmt.Println("\n❌ Authentication failed.")
+			return err
+		}
+
+		result, err := client.CreateStandingOrder(standingOrderOptions)
+		done <- true
+		<-done
+
+		if err != nil {
+			fmt.Println("\n❌ Standing order failed.")
+			return fmt.Errorf("error creating standing order: %w", err)
+		}
+
+		fmt.Println("\n✔ Standing order request accepted!")
+		fmt.Println("--------------------")
+		fmt.Printf("Response Code: %s\n", result.ResponseHeader.ResponseCode)
+		fmt.Printf("Description: %s\n", result.ResponseHeader.ResponseDescription)
+		fmt.Printf("Reference: %s\n", result.ResponseHeader.ResponseRefID)
+		fmt.Println("--------------------")
+		fmt.Println("💡 Tip: The customer must approve the standing order on their phone.")
+
+		return nil
+	},
+}
+
+// parseFrequency parses a --frequency value given as a code (1-8) or a name.
+func parseFrequency(value string) (int, error) {
+	if frequency, ok := standingOrderFrequencies[strings.ToLower(value)]; ok {
+		return frequency, nil
+	}
+
+	frequency, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid --frequency '%s': use a code from 1 to 8 or a name such as monthly", value)
+	}
+	if err := mpesa.ValidateFrequency(frequency); err != nil {
+		return 0, err
+	}
+	return frequency, nil
+}
+
+func init() {
+	standingOrderCmd.AddCommand(standingOrderCreateCmd)
+	standingOrderCreateCmd.Flags().StringVar(&standingOrderOptions.Name, "name", "", "Unique name of the standing order for the customer (required)")
+	standingOrderCreateCmd.Flags().StringVar(&standingOrderOptions.PhoneNumber, "phone", "", "Customer phone number, e.g. 0712345678 (required)")
+	standingOrderCreateCmd.Flags().IntVar(&standingOrderOptions.Amount, "amount", 0, "Amount of each payment in KES (required)")
+	standingOrderCreateCmd.Flags().StringVar(&standingOrderFrequency, "frequency", "", "Payment frequency, as a code from 1 to 8 or a name (required)")
+	standingOrderCreateCmd.Flags().StringVar(&standingOrderStart, "start", "", "Date of the first payment as YYYY-MM-DD (required)")
+	standingOrderCreateCmd.Flags().StringVar(&standingOrderEnd, "end", "", "Last date of the standing order as YYYY-MM-DD (required)")
+	standingOrderCreateCmd.Flags().StringVar(&standingOrderOptions.AccountReference, "account-ref", "", "Account reference shown to the customer, up to 12 characters (default is --name)")
+	standingOrderCreateCmd.Flags().StringVar(&standingOrderOptions.TransactionDesc, "desc", "Subscription", "Transaction description, up to 13 characters")
+	standingOrderCreateCmd.Flags().StringVar(&standingOrderType, "type", "paybill", "Payment type: paybill or buygoods")
+	standingOrderCreateCmd.Flags().StringVar(&standingOrderOptions.CallbackURL, "callback-url", "", "URL that receives the result (default is callback_url from config)")
+	for _, name := range []string{"name", "phone", "amount", "frequency", "start", "end"} {
+		_ = standingOrderCreateCmd.MarkFlagRequired(name)
+	}
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+)
+
+// TestParseFrequency tests --frequency codes and names
+func TestParseFrequency(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int
+	}{
+		{value: "4", expected: mpesa.FrequencyMonthly},
+		{value: "monthly", expected: mpesa.FrequencyMonthly},
+		{value: "Weekly", expected: mpesa.FrequencyWeekly},
+		{value: "8", expected: mpesa.FrequencyYearly},
+	}
+
+	for _, tt := range tests {
+		got, err := parseFrequency(tt.value)
+		if err != nil || got != tt.expected {
+			t.Errorf("parseFrequency(%q): expected %d, got %d (%v)", tt.value, tt.expected, got, err)
+		}
+	}
+
+	for _, invalid := range []string{"0", "9", "fortnightly", ""} {
+		if _, err := parseFrequency(invalid); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// stkCmd represents the stk parent command
+var stkCmd = &cobra.Command{
+	Use:   "stk",
+	Short: "Lipa Na M-Pesa Online (STK Push) payments",
+	Long: `Parent command for M-Pesa Express (STK Push) operations.
+
+STK Push requests are signed with the passkey of the selected profile.
+Store it with: mpesa-cli login --passkey`,
+}
+
+func init() {
+	rootCmd.AddCommand(stkCmd)
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+var (
+	stkPushOptions mpesa.STKPushOptions
+	stkPushType    string
+)
+
+// stkTransactionTypes maps the --type flag values to STK Push transaction types
+var stkTransactionTypes = map[string]string{
+	"paybill":  mpesa.CustomerPayBillOnline,
+	"buygoods": mpesa.CustomerBuyGoodsOnline,
+}
+
+var stkPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Prompt a customer to pay via M-Pesa Express",
+	Long: `Send an STK Push payment prompt to the customer's phone using the business shortcode
+of the selected profile. Paybill payments go to the shortcode; for Buy Goods payments use
+--type buygoods and pass the till number with --till.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		transactionType, ok := stkTransactionTypes[stkPushType]
+		if !ok {
+			return fmt.Errorf("--type must be paybill or buygoods, got: %s", stkPushType)
+		}
+		stkPushOptions.TransactionType = transactionType
+
+		passkey, err := mpesa.GetProfilePasskey(activeProfile())
+		if err != nil {
+			fmt.Println("❌ Failed to get passkey.")
+			return fmt.Errorf("error getting passkey: %w", err)
+		}
+
+		done := make(chan bool)
+		go showSpinner(fmt.Sprintf("Sending payment prompt to %s", stkPushOptions.PhoneNumber), done)
+
+		client, err := profileClient(mpesa.WithPasskey(passkey))
+		if err != nil {
+			done <- true
+			<-done
+			fmt.Println("\n❌ Authentication failed.")
+			return err
+		}
+
+		result, err := client.STKPush(stkPushOptions)
+		done <- true
+		<-done
+
+		if err != nil {
+			fmt.Println("\n❌ STK push failed.")
+			return fmt.Errorf("error sending STK push: %w", err)
+		}
+
+		fmt.Println("\n✔ STK push sent!")
+		fmt.Println("--------------------")
+		fmt.Printf("Response Code: %s\n", result.ResponseCode)
+		fmt.Printf("Description: %s\n", result.ResponseDescription)
+		fmt.Printf("Customer Message: %s\n", result.CustomerMessage)
+		fmt.Printf("Merchant Request ID: %s\n", result.MerchantRequestID)
+		fmt.Printf("Checkout Request ID: %s\n", result.CheckoutRequestID)
+		fmt.Println("--------------------")
+		fmt.Printf("💡 Tip: Run `mpesa-cli stk query --checkout-id %s --wait` to wait for the result.\n", result.CheckoutRequestID)
+
+		return nil
+	},
+}
+
+func init() {
+	stkCmd.AddCommand(stkPushCmd)
+	stkPushCmd.Flags().StringVar(&stkPushOptions.PhoneNumber, "phone", "", "Customer phone number, e.g. 0712345678 (required)")
+	stkPushCmd.Flags().IntVar(&stkPushOptions.Amount, "amount", 0, "Amount to charge in KES (required)")
+	stkPushCmd.Flags().StringVar(&stkPushOptions.AccountReference, "account-ref", "", "Account reference shown to the customer, up to 12 characters (required)")
+	stkPushCmd.Flags().StringVar(&stkPushOptions.TransactionDesc, "desc", "Payment", "Transaction description, up to 13 characters")
+	stkPushCmd.Flags().StringVar(&stkPushType, "type", "paybill", "Payment type: paybill (CustomerPayBillOnline) or buygoods (CustomerBuyGoodsOnline)")
+	stkPushCmd.Flags().StringVar(&stkPushOptions.PartyB, "till", "", "Till number receiving Buy Goods payments")
+	stkPushCmd.Flags().StringVar(&stkPushOptions.CallbackURL, "callback-url", "", "URL that receives the payment result (default is callback_url from config)")
+	_ = stkPushCmd.MarkFlagRequired("phone")
+	_ = stkPushCmd.MarkFlagRequired("amount")
+	_ = stkPushCmd.MarkFlagRequired("account-ref")
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkoutRequestID string
+	stkQueryWait      bool
+	stkQueryTimeout   time.Duration
+)
+
+var stkQueryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "Check the result of an STK Push payment",
+	Long: `Query the status of an STK Push payment by its Checkout Request ID.
+
+With --wait, the query is repeated with increasing intervals until the customer
+completes or cancels the payment, or the prompt times out.
+
+Exit codes:
+  0  payment completed
+  1  error
+  2  cancelled by the customer (result code 1032)
+  3  customer did not respond in time (result code 1037)
+  4  payment failed with another result code
+  5  payment still pending`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		passkey, err := mpesa.GetProfilePasskey(activeProfile())
+		if err != nil {
+			fmt.Println("❌ Failed to get passkey.")
+			return fmt.Errorf("error getting passkey: %w", err)
+		}
+
+		message := fmt.Sprintf("Querying status for checkout request ID: %s", checkoutRequestID)
+		if stkQueryWait {
+			message = "Waiting for the customer to complete the payment..."
+		}
+
+		done := make(chan bool)
+		go showSpinner(message, done)
+
+		client, err := profileClient(mpesa.WithPasskey(passkey))
+		if err != nil {
+			done <- true
+			<-done
+			fmt.Println("\n❌ Authentication failed.")
+			return err
+		}
+
+		result, err := client.STKQuery(checkoutRequestID)
+		if stkQueryWait && errors.Is(err, mpesa.ErrSTKPending) {
+			result, err = client.WaitForSTKResult(checkoutRequestID, stkQueryTimeout)
+		}
+		done <- true
+		<-done
+
+		// Outcomes below are reported through the exit code, not as usage errors
+		cmd.SilenceUsage = true
+
+		if errors.Is(err, mpesa.ErrSTKPending) {
+			fmt.Println("\n⏳ Payment is still pending.")
+			return withExitCode(exitPending, "payment %s is still pending", checkoutRequestID)
+		}
+		if err != nil {
+			fmt.Println("\n❌ Query failed.")
+			return fmt.Errorf("error querying STK push: %w", err)
+		}
+
+		fmt.Println()
+		fmt.Println("--------------------")
+		fmt.Printf("Result Code: %s\n", result.ResultCode)
+		fmt.Printf("Result: %s\n", result.ResultDesc)
+		fmt.Printf("Merchant Request ID: %s\n", result.MerchantRequestID)
+		fmt.Printf("Checkout Request ID: %s\n", result.CheckoutRequestID)
+		fmt.Println("--------------------")
+
+		return stkResultError(result.ResultCode, result.ResultDesc)
+	},
+}
+
+// stkResultError maps an STK Push result code to nil for a completed payment,
+// or to an error carrying the exit code of the outcome.
+func stkResultError(resultCode, resultDesc string) error {
+	switch resultCode {
+	case mpesa.STKResultSuccess:
+		fmt.Println("✔ Payment completed.")
+		return nil
+	case mpesa.STKResultCancelled:
+		fmt.Println("❌ Payment cancelled by the customer.")
+		return withExitCode(exitCancelled, "payment cancelled: %s", resultDesc)
+	case mpesa.STKResultTimeout:
+		fmt.Println("❌ The customer did not respond in time.")
+		return withExitCode(exitTimedOut, "payment timed out: %s", resultDesc)
+	default:
+		fmt.Println("❌ Payment failed.")
+		return withExitCode(exitFailed, "payment failed with result code %s: %s", resultCode, resultDesc)
+	}
+}
+
+func init() {
+	stkCmd.AddCommand(stkQueryCmd)
+	stkQueryCmd.Flags().StringVar(&checkoutRequestID, "checkout-id", "", "The Checkout Request ID returned by 'stk push' (required)")
+	stkQueryCmd.Flags().BoolVar(&stkQueryWait, "wait", false, "Keep polling until the payment is completed, cancelled or times out")
+	stkQueryCmd.Flags().DurationVar(&stkQueryTimeout, "timeout", 2*time.Minute, "Maximum time to wait with --wait")
+	_ = stkQueryCmd.MarkFlagRequired("checkout-id")
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+)
+
+// TestStkPushCommandStructure tests the stk push command and its required flags
+func TestStkPushCommandStructure(t *testing.T) {
+	if stkPushCmd.Parent() != stkCmd {
+		t.Error("expected push to be a subcommand of stk")
+	}
+
+	if stkPushCmd.RunE == nil {
+		t.Error("expected RunE to be set")
+	}
+
+	for _, name := range []string{"phone", "amount", "account-ref"} {
+		flag := stkPushCmd.Flags().Lookup(name)
+		if flag == nil {
+			t.Errorf("expected --%s flag", name)
+			continue
+		}
+		if _, ok := flag.Annotations["cobra_annotation_bash_completion_one_required_flag"]; !ok {
+			t.Errorf("expected --%s to be required", name)
+		}
+	}
+}
+
+// TestStkPushRejectsUnknownType tests that --type is validated before any API call
+func TestStkPushRejectsUnknownType(t *testing.T) {
+	oldType := stkPushType
+	defer func() { stkPushType = oldType }()
+
+	stkPushType = "invalid"
+	if err := stkPushCmd.RunE(stkPushCmd, nil); err == nil {
+		t.Error("expected error for unknown payment type")
+	}
+}
+
+// TestStkResultErrorExitCodes tests that each STK Push outcome maps to its own exit code
+func TestStkResultErrorExitCodes(t *testing.T) {
+	tests := []struct {
+		resultCode string
+		exitCode   int
+	}{
+		{resultCode: mpesa.STKResultSuccess, exitCode: 0},
+		{resultCode: mpesa.STKResultCancelled, exitCode: exitCancelled},
+		{resultCode: mpesa.STKResultTimeout, exitCode: exitTimedOut},
+		{resultCode: "2001", exitCode: exitFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.resultCode, func(t *testing.T) {
+			err := stkResultError(tt.resultCode, "description")
+
+			if tt.exitCode == 0 {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			var exitErr *exitError
+			if !errors.As(err, &exitErr) {
+				t.Fatalf("expected exitError, got %v", err)
+			}
+			if exitErr.code != tt.exitCode {
+				t.Errorf("expected exit code %d, got %d", tt.exitCode, exitErr.code)
+			}
+		})
+	}
+}
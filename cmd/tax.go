@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// taxCmd represents the tax parent command
+var taxCmd = &cobra.Command{
+	Use:   "tax",
+	Short: "Tax payments to the Kenya Revenue Authority",
+	Long:  `Parent command for paying taxes to KRA from the business shortcode of the selected profile.`,
+}
+
+func init() {
+	rootCmd.AddCommand(taxCmd)
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/martwebber/mpesa-cli/pkg/mpesa"
+	"github.com/spf13/cobra"
+)
+
+var taxRemitOptions mpesa.TaxRemitOptions
+
+var taxRemitCmd = &cobra.Command{
+	Use:   "remit",
+	Short: "Remit tax to KRA",
+	Long: `Pay tax to the KRA shortcode (572572) for a payment registration number (PRN)
+generated on iTax. The request is acknowledged immediately; the outcome is
+delivered to result_url.
+
+In production you are asked to confirm the payment unless --yes is given.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAsyncRequest(asyncRequest{
+			name:     "tax remittance",
+			summary:  fmt.Sprintf("remit KES %d to KRA for PRN %s", taxRemitOptions.Amount, taxRemitOptions.PRN),
+			progress: fmt.Sprintf("Remitting KES %d to KRA", taxRemitOptions.Amount),
+			send: func(client *mpesa.Client) (*mpesa.AsyncResponse, error) {
+				return client.RemitTax(taxRemitOptions)
+			},
+		})
+	},
+}
+
+func init() {
+	taxCmd.AddCommand(taxRemitCmd)
+	taxRemitCmd.Flags().IntVar(&taxRemitOptions.Amount, "amount", 0, "Amount to pay in KES (required)")
+	taxRemitCmd.Flags().StringVar(&taxRemitOptions.PRN, "prn", "", "Payment registration number from KRA (required)")
+	taxRemitCmd.Flags().StringVar(&taxRemitOptions.Remarks, "remarks", "Tax Remittance", "Remarks sent with the payment, 2 to 100 characters")
+	addYesFlag(taxRemitCmd)
+	_ = taxRemitCmd.MarkFlagRequired("amount")
+	_ = taxRemitCmd.MarkFlagRequired("prn")
+}
@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"bufio"
+	"encoding/csv"
 	"fmt"
+	"os"
 	"strings"
 	"time"
+
+	"golang.org/x/term"
 )
 
 func showSpinner(message string, done chan bool) {
@@ -22,3 +27,76 @@ func showSpinner(message string, done chan bool) {
 		}
 	}
 }
+
+// maskSecret hides all but the first four characters of a secret value.
+func maskSecret(secret string) string {
+	if len(secret) <= 4 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:4] + strings.Repeat("*", 8)
+}
+
+// valueOrNone returns value, or "(not set)" if it is empty.
+func valueOrNone(value string) string {
+	if value == "" {
+		return "(not set)"
+	}
+	return value
+}
+
+// readCSVRecords reads a CSV file whose first row names the columns and returns
+// one map per data row, keyed by the lowercased column names.
+func readCSVRecords(path string) ([]map[string]string, error) {
+	file, err := os.Open(path) // #nosec G304 - path is supplied by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV file: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("CSV file %s is empty", path)
+	}
+
+	header := make([]string, len(rows[0]))
+	for i, name := range rows[0] {
+		header[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+
+	records := make([]map[string]string, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		record := make(map[string]string, len(header))
+		for i, value := range row {
+			record[header[i]] = strings.TrimSpace(value)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// readSecret reads a secret from stdin: after a hidden prompt on a terminal, or
+// as the first line of piped input otherwise. Only the line ending is removed;
+// spaces are kept, as they may be part of a password.
+func readSecret(prompt string) (string, error) {
+	var secret string
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Print(prompt)
+		data, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Println()
+		if err != nil {
+			return "", err
+		}
+		secret = string(data)
+	} else {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("no input on stdin")
+		}
+		secret = line
+	}
+	return strings.TrimRight(secret, "\r\n"), nil
+}
@@ -3,11 +3,12 @@ package mpesa
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
-
-	keyring "github.com/zalando/go-keyring"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const serviceName = "mpesa-cli"
@@ -18,8 +19,9 @@ type authResponse struct {
 	ExpiresIn   string `json:"expires_in"`
 }
 
-// AuthURL is the M-Pesa OAuth endpoint URL (can be modified for testing)
-var AuthURL = "https://sandbox.safaricom.co.ke/oauth/v1/generate?grant_type=client_credentials"
+// AuthURL is the M-Pesa OAuth endpoint URL used by the package-level GetAccessToken
+// (can be modified for testing). Clients derive their OAuth endpoint from their base URL.
+var AuthURL = SandboxBaseURL + oauthPath
 
 // GetAccessToken authenticates with the M-Pesa API using consumer credentials.
 // It sends a request to the M-Pesa OAuth endpoint and returns an access token
@@ -34,80 +36,258 @@ var AuthURL = "https://sandbox.safaricom.co.ke/oauth/v1/generate?grant_type=clie
 //   - string: The access token for API authentication
 //   - error: Any error that occurred during authentication
 func GetAccessToken(consumerKey, consumerSecret string) (string, error) {
-	req, err := http.NewRequest("GET", AuthURL, nil)
+	accessToken, _, err := NewClient(nil).fetchToken(AuthURL, consumerKey, consumerSecret)
+	return accessToken, err
+}
+
+// fetchToken requests an access token from the OAuth endpoint at url and
+// returns it together with its lifetime. The lifetime is zero if the response
+// did not include a valid expires_in value.
+func (c *Client) fetchToken(url, consumerKey, consumerSecret string) (string, time.Duration, error) {
+	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return "", err
+		return "", 0, err
 	}
 
 	auth := base64.StdEncoding.EncodeToString([]byte(consumerKey + ":" + consumerSecret))
 	req.Header.Set("Authorization", "Basic "+auth)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	body, err := c.do(req)
 	if err != nil {
-		return "", err
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			return "", 0, fmt.Errorf("authentication failed with status %d: %s", apiErr.StatusCode, apiErr.Body)
+		}
+		return "", 0, err
+	}
+
+	var result authResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", 0, fmt.Errorf("failed to parse auth response: %v", err)
+	}
+
+	expiresIn, err := strconv.Atoi(result.ExpiresIn)
+	if err != nil || expiresIn < 0 {
+		expiresIn = 0
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	body, err := io.ReadAll(resp.Body)
+	return result.AccessToken, time.Duration(expiresIn) * time.Second, nil
+}
+
+// Account names of the stored credentials
+const (
+	consumerKeyAccount    = "consumer_key"
+	consumerSecretAccount = "consumer_secret"
+	passkeyAccount        = "passkey"
+
+	initiatorPasswordAccount = "initiator_password"
+)
+
+// credentialAccounts lists every credential stored per profile
+var credentialAccounts = []string{consumerKeyAccount, consumerSecretAccount, passkeyAccount, initiatorPasswordAccount}
+
+// credentialAccount returns the account holding the named credential of a profile.
+// The default profile uses the bare names so that credentials stored before profiles existed keep working.
+func credentialAccount(profile, name string) string {
+	if profile == "" || profile == DefaultProfile {
+		return name
+	}
+	return profile + "/" + name
+}
+
+// loginHint returns the login command to run for a profile
+func loginHint(profile string) string {
+	if profile == "" || profile == DefaultProfile {
+		return "mpesa-cli login"
+	}
+	return "mpesa-cli login --profile " + profile
+}
+
+// getCredential returns the named credential of a profile from the active credential store.
+func getCredential(profile, name string) (string, error) {
+	store, err := ActiveCredentialStore()
 	if err != nil {
 		return "", err
 	}
+	return store.Get(credentialAccount(profile, name))
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("authentication failed with status %d: %s", resp.StatusCode, string(body))
+// setCredential stores the named credential of a profile in the active credential store.
+func setCredential(profile, name, value string) error {
+	store, err := ActiveCredentialStore()
+	if err != nil {
+		return err
+	}
+	if err := store.Set(credentialAccount(profile, name), value); err != nil {
+		return fmt.Errorf("failed to store %s in %s: %w", strings.ReplaceAll(name, "_", " "), store.Name(), err)
 	}
+	return nil
+}
 
-	var result authResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return "", fmt.Errorf("failed to parse auth response: %v", err)
+// deleteCredential removes the named credential of a profile from the active credential store.
+func deleteCredential(profile, name string) error {
+	store, err := ActiveCredentialStore()
+	if err != nil {
+		return err
 	}
+	if err := store.Delete(credentialAccount(profile, name)); err != nil {
+		return fmt.Errorf("failed to delete %s from %s: %w", strings.ReplaceAll(name, "_", " "), store.Name(), err)
+	}
+	return nil
+}
+
+// SetCredentials securely stores the M-Pesa consumer key and secret of the default
+// profile. See SetProfileCredentials.
+func SetCredentials(consumerKey, consumerSecret string) error {
+	return SetProfileCredentials(DefaultProfile, consumerKey, consumerSecret)
+}
 
-	return result.AccessToken, nil
+// GetCredentials retrieves the M-Pesa consumer key and secret of the default profile.
+// See GetProfileCredentials.
+func GetCredentials() (string, string, error) {
+	return GetProfileCredentials(DefaultProfile)
 }
 
-// SetCredentials securely stores the M-Pesa consumer key and secret in the system keychain.
-// The credentials are encrypted and stored using the operating system's native
-// keychain/credential manager (Keychain on macOS, Credential Manager on Windows,
-// Secret Service on Linux).
+// SetProfileCredentials securely stores the M-Pesa consumer key and secret of a profile
+// in the active credential store (see ActiveCredentialStore). With the default keyring
+// backend they are kept by the operating system's native keychain/credential manager
+// (Keychain on macOS, Credential Manager on Windows, Secret Service on Linux).
 //
 // Parameters:
+//   - profile: The profile the credentials belong to
 //   - consumerKey: The consumer key to store
 //   - consumerSecret: The consumer secret to store
 //
 // Returns:
 //   - error: Any error that occurred during storage
-func SetCredentials(consumerKey, consumerSecret string) error {
-	err := keyring.Set(serviceName, "consumer_key", consumerKey)
-	if err != nil {
-		return fmt.Errorf("failed to store consumer key in keychain: %w", err)
+func SetProfileCredentials(profile, consumerKey, consumerSecret string) error {
+	if err := setCredential(profile, consumerKeyAccount, consumerKey); err != nil {
+		return err
 	}
-
-	err = keyring.Set(serviceName, "consumer_secret", consumerSecret)
-	if err != nil {
-		return fmt.Errorf("failed to store consumer secret in keychain: %w", err)
-	}
-
-	return nil
+	return setCredential(profile, consumerSecretAccount, consumerSecret)
 }
 
-// GetCredentials retrieves the M-Pesa consumer key and secret from the system keychain.
-// The credentials must have been previously stored using SetCredentials.
+// GetProfileCredentials retrieves the M-Pesa consumer key and secret of a profile from the active credential store.
+// The credentials must have been previously stored using SetProfileCredentials.
 //
 // Returns:
 //   - string: The consumer key
 //   - string: The consumer secret
 //   - error: Any error that occurred during retrieval, including if credentials are not found
-func GetCredentials() (string, string, error) {
-	consumerKey, err := keyring.Get(serviceName, "consumer_key")
+func GetProfileCredentials(profile string) (string, string, error) {
+	consumerKey, err := getCredential(profile, consumerKeyAccount)
 	if err != nil {
-		return "", "", fmt.Errorf("could not retrieve consumer key. Please run 'mpesa-cli login' again: %w", err)
+		return "", "", fmt.Errorf("could not retrieve consumer key. Please run '%s' again: %w", loginHint(profile), err)
 	}
 
-	consumerSecret, err := keyring.Get(serviceName, "consumer_secret")
+	consumerSecret, err := getCredential(profile, consumerSecretAccount)
 	if err != nil {
-		return "", "", fmt.Errorf("could not retrieve consumer secret. Please run 'mpesa-cli login' again: %w", err)
+		return "", "", fmt.Errorf("could not retrieve consumer secret. Please run '%s' again: %w", loginHint(profile), err)
 	}
 
 	return consumerKey, consumerSecret, nil
 }
+
+// SetProfilePasskey securely stores the Lipa Na M-Pesa Online passkey of a profile.
+func SetProfilePasskey(profile, passkey string) error {
+	return setCredential(profile, passkeyAccount, passkey)
+}
+
+// GetProfilePasskey retrieves the Lipa Na M-Pesa Online passkey of a profile.
+func GetProfilePasskey(profile string) (string, error) {
+	passkey, err := getCredential(profile, passkeyAccount)
+	if err != nil {
+		return "", fmt.Errorf("could not retrieve passkey. Please run '%s --passkey': %w", loginHint(profile), err)
+	}
+	return passkey, nil
+}
+
+// SetProfileInitiatorPassword securely stores the initiator password of a profile.
+// The SecurityCredential is then generated from it on demand; see ResolveSecurityCredential.
+func SetProfileInitiatorPassword(profile, password string) error {
+	return setCredential(profile, initiatorPasswordAccount, password)
+}
+
+// GetProfileInitiatorPassword retrieves the initiator password of a profile.
+// It returns ErrNoInitiatorPassword if none is stored.
+func GetProfileInitiatorPassword(profile string) (string, error) {
+	password, err := getCredential(profile, initiatorPasswordAccount)
+	if errors.Is(err, ErrCredentialNotFound) {
+		return "", ErrNoInitiatorPassword
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not retrieve initiator password: %w", err)
+	}
+	return password, nil
+}
+
+// DeleteProfileInitiatorPassword removes the initiator password of a profile.
+// It is not an error if none is stored.
+func DeleteProfileInitiatorPassword(profile string) error {
+	return deleteCredential(profile, initiatorPasswordAccount)
+}
+
+// DeleteProfileCredentials removes all credentials of a profile from the active credential store.
+// Credentials that are not stored are ignored.
+func DeleteProfileCredentials(profile string) error {
+	for _, name := range credentialAccounts {
+		if err := deleteCredential(profile, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CredentialStatus describes which credentials are stored for a profile.
+type CredentialStatus struct {
+	// ConsumerKey is the stored consumer key, empty if there is none
+	ConsumerKey string
+
+	// ConsumerSecret, Passkey and InitiatorPassword report whether those credentials are stored
+	ConsumerSecret    bool
+	Passkey           bool
+	InitiatorPassword bool
+}
+
+// GetProfileCredentialStatus reports which credentials are stored for a profile.
+// Missing credentials are not an error; failing to query the credential store is.
+func GetProfileCredentialStatus(profile string) (*CredentialStatus, error) {
+	stored := map[string]string{}
+	for _, name := range credentialAccounts {
+		value, err := getCredential(profile, name)
+		if errors.Is(err, ErrCredentialNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not look up %s: %w", strings.ReplaceAll(name, "_", " "), err)
+		}
+		stored[name] = value
+	}
+
+	return &CredentialStatus{
+		ConsumerKey:       stored[consumerKeyAccount],
+		ConsumerSecret:    stored[consumerSecretAccount] != "",
+		Passkey:           stored[passkeyAccount] != "",
+		InitiatorPassword: stored[initiatorPasswordAccount] != "",
+	}, nil
+}
+
+// RotateProfileCredentials replaces the consumer key and secret of a profile. If storing
+// either value fails, the previous credentials are put back, so the profile is never left
+// with a key and secret that do not belong together.
+func RotateProfileCredentials(profile, consumerKey, consumerSecret string) error {
+	oldKey, oldSecret, err := GetProfileCredentials(profile)
+	if err != nil {
+		return err
+	}
+
+	if err := SetProfileCredentials(profile, consumerKey, consumerSecret); err != nil {
+		if restoreErr := SetProfileCredentials(profile, oldKey, oldSecret); restoreErr != nil {
+			return fmt.Errorf("%w (restoring the previous credentials also failed: %v)", err, restoreErr)
+		}
+		return err
+	}
+
+	return nil
+}
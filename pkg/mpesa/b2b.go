@@ -0,0 +1,192 @@
+package mpesa
+
+import (
+	"fmt"
+)
+
+// Command IDs of Business to Business (B2B) payments
+const (
+	// BusinessPayBill pays into a Paybill number
+	BusinessPayBill = "BusinessPayBill"
+
+	// BusinessBuyGoods pays into a Till number
+	BusinessBuyGoods = "BusinessBuyGoods"
+
+	// DisburseFundsToBusiness moves funds from the utility account to another business
+	DisburseFundsToBusiness = "DisburseFundsToBusiness"
+
+	// BusinessToBusinessTransfer moves funds between the working accounts of two businesses
+	BusinessToBusinessTransfer = "BusinessToBusinessTransfer"
+
+	// MerchantToMerchantTransfer moves funds between the working accounts of two merchants
+	MerchantToMerchantTransfer = "MerchantToMerchantTransfer"
+
+	// BusinessPayToBulk loads the B2C utility account of a shortcode from a working account
+	BusinessPayToBulk = "BusinessPayToBulk"
+)
+
+// Identifier types of the parties of initiator-based requests
+const (
+	// IdentifierMSISDN identifies a party by phone number
+	IdentifierMSISDN = "1"
+
+	// IdentifierTill identifies a party by Till number
+	IdentifierTill = "2"
+
+	// IdentifierShortcode identifies a party by organization shortcode (Paybill)
+	IdentifierShortcode = "4"
+)
+
+// Receiver types of B2B payments
+const (
+	ReceiverPaybill = "paybill"
+	ReceiverTill    = "till"
+)
+
+const (
+	b2bPaymentPath = "/mpesa/b2b/v1/paymentrequest"
+
+	// maxB2BAccountReferenceLength is the account reference limit of the B2B API
+	maxB2BAccountReferenceLength = 13
+)
+
+// B2BOptions describes a payment from the business shortcode to another business.
+type B2BOptions struct {
+	// Receiver is the Paybill or Till number receiving the funds
+	Receiver string
+
+	// ReceiverType is ReceiverPaybill (the default) or ReceiverTill
+	ReceiverType string
+
+	// Amount is the amount to pay in whole shillings
+	Amount int
+
+	// AccountReference is the account number at the receiving Paybill (up to 13 characters)
+	AccountReference string
+
+	// CommandID defaults to BusinessPayBill for Paybills and BusinessBuyGoods for Tills
+	CommandID string
+
+	// Remarks are additional information for the payment (2 to 100 characters)
+	Remarks string
+}
+
+// b2bRequest represents the JSON payload sent to the M-Pesa B2B Payment Request API.
+type b2bRequest struct {
+	// Initiator is the name of the user initiating the payment
+	Initiator string `json:"Initiator"`
+
+	// SecurityCredential is the encrypted credential of the initiator
+	SecurityCredential string `json:"SecurityCredential"`
+
+	// CommandID specifies the type of B2B payment
+	CommandID string `json:"CommandID"`
+
+	// SenderIdentifierType is the identifier type of PartyA
+	SenderIdentifierType string `json:"SenderIdentifierType"`
+
+	// RecieverIdentifierType is the identifier type of PartyB (sic, as spelled by the API)
+	RecieverIdentifierType string `json:"RecieverIdentifierType"`
+
+	// Amount is the amount being paid
+	Amount int `json:"Amount"`
+
+	// PartyA is the organization's shortcode sending the funds
+	PartyA string `json:"PartyA"`
+
+	// PartyB is the shortcode receiving the funds
+	PartyB string `json:"PartyB"`
+
+	// AccountReference is the account number at the receiving Paybill
+	AccountReference string `json:"AccountReference"`
+
+	// Remarks are additional information for the payment
+	Remarks string `json:"Remarks"`
+
+	// QueueTimeOutURL is the path that stores information of time out transaction
+	QueueTimeOutURL string `json:"QueueTimeOutURL"`
+
+	// ResultURL is the path that receives the result of the payment
+	ResultURL string `json:"ResultURL"`
+}
+
+// B2BPayment pays another business from the business shortcode using the initiator,
+// security credential and callback URLs from the client's config. The outcome
+// of the payment is delivered to the ResultURL.
+func (c *Client) B2BPayment(opts B2BOptions) (*AsyncResponse, error) {
+	if err := c.requireInitiator("B2B payments"); err != nil {
+		return nil, err
+	}
+
+	if opts.Receiver == "" {
+		return nil, fmt.Errorf("the receiving shortcode is required")
+	}
+	if opts.Amount < 1 {
+		return nil, fmt.Errorf("amount must be at least 1, got %d", opts.Amount)
+	}
+	if len(opts.AccountReference) > maxB2BAccountReferenceLength {
+		return nil, fmt.Errorf("account reference must be at most %d characters", maxB2BAccountReferenceLength)
+	}
+	if len(opts.Remarks) < minRemarksLength || len(opts.Remarks) > maxRemarksLength {
+		return nil, fmt.Errorf("remarks must be %d to %d characters", minRemarksLength, maxRemarksLength)
+	}
+
+	receiverType := opts.ReceiverType
+	if receiverType == "" {
+		receiverType = ReceiverPaybill
+	}
+
+	var receiverIdentifierType, defaultCommandID string
+	switch receiverType {
+	case ReceiverPaybill:
+		receiverIdentifierType, defaultCommandID = IdentifierShortcode, BusinessPayBill
+	case ReceiverTill:
+		receiverIdentifierType, defaultCommandID = IdentifierTill, BusinessBuyGoods
+	default:
+		return nil, fmt.Errorf("receiver type must be %s or %s, got: %s", ReceiverPaybill, ReceiverTill, receiverType)
+	}
+
+	commandID := opts.CommandID
+	if commandID == "" {
+		commandID = defaultCommandID
+	}
+	switch commandID {
+	case BusinessPayBill, BusinessBuyGoods, DisburseFundsToBusiness, BusinessToBusinessTransfer, MerchantToMerchantTransfer, BusinessPayToBulk:
+	default:
+		return nil, fmt.Errorf("unknown B2B command ID: %s", commandID)
+	}
+	if commandID == BusinessPayBill && opts.AccountReference == "" {
+		return nil, fmt.Errorf("an account reference is required for %s", BusinessPayBill)
+	}
+
+	reqBody := b2bRequest{
+		Initiator:              c.config.Initiator,
+		SecurityCredential:     c.config.SecurityCredential,
+		CommandID:              commandID,
+		SenderIdentifierType:   IdentifierShortcode,
+		RecieverIdentifierType: receiverIdentifierType,
+		Amount:                 opts.Amount,
+		PartyA:                 c.config.BusinessShortcode,
+		PartyB:                 opts.Receiver,
+		AccountReference:       opts.AccountReference,
+		Remarks:                opts.Remarks,
+		QueueTimeOutURL:        c.config.QueueTimeOutURL,
+		ResultURL:              c.config.ResultURL,
+	}
+
+	var result AsyncResponse
+	if err := c.postJSON(b2bPaymentPath, reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// B2CTopUp loads the B2C utility account of the receiving shortcode from the working
+// account of the business shortcode, using BusinessPayToBulk. It is otherwise sent
+// like B2BPayment; the ReceiverType and CommandID of opts are ignored.
+func (c *Client) B2CTopUp(opts B2BOptions) (*AsyncResponse, error) {
+	opts.ReceiverType = ReceiverPaybill
+	opts.CommandID = BusinessPayToBulk
+	return c.B2BPayment(opts)
+}
@@ -0,0 +1,123 @@
+package mpesa
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestB2BPayment tests that identifier types and command IDs follow the receiver type
+func TestB2BPayment(t *testing.T) {
+	var received b2bRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mpesa/b2b/v1/paymentrequest" {
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"ConversationID":"AG_20240706_3010","OriginatorConversationID":"5118-111210482-1","ResponseCode":"0","ResponseDescription":"Accept the service request successfully."}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(testConfig(), WithBaseURL(server.URL), WithTokenSource(StaticToken("token")))
+
+	tests := []struct {
+		name           string
+		opts           B2BOptions
+		commandID      string
+		receiverIDType string
+	}{
+		{
+			name:           "paybill",
+			opts:           B2BOptions{Receiver: "600000", Amount: 1000, AccountReference: "FLOAT", Remarks: "Weekly float"},
+			commandID:      BusinessPayBill,
+			receiverIDType: IdentifierShortcode,
+		},
+		{
+			name:           "till",
+			opts:           B2BOptions{Receiver: "500500", ReceiverType: ReceiverTill, Amount: 1000, Remarks: "Weekly float"},
+			commandID:      BusinessBuyGoods,
+			receiverIDType: IdentifierTill,
+		},
+		{
+			name:           "explicit command",
+			opts:           B2BOptions{Receiver: "600000", Amount: 1000, CommandID: BusinessToBusinessTransfer, Remarks: "Weekly float"},
+			commandID:      BusinessToBusinessTransfer,
+			receiverIDType: IdentifierShortcode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := client.B2BPayment(tt.opts)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if result.ConversationID != "AG_20240706_3010" {
+				t.Errorf("unexpected response %+v", result)
+			}
+			if received.CommandID != tt.commandID || received.RecieverIdentifierType != tt.receiverIDType {
+				t.Errorf("expected %s with receiver type %s, got %+v", tt.commandID, tt.receiverIDType, received)
+			}
+			if received.SenderIdentifierType != IdentifierShortcode || received.PartyA != "600986" || received.PartyB != tt.opts.Receiver {
+				t.Errorf("unexpected parties %+v", received)
+			}
+		})
+	}
+}
+
+// TestB2BPaymentValidation tests that invalid payments are rejected before anything is sent
+func TestB2BPaymentValidation(t *testing.T) {
+	client := NewClient(testConfig(), WithBaseURL("http://127.0.0.1:0"), WithTokenSource(StaticToken("token")))
+
+	tests := []struct {
+		name      string
+		opts      B2BOptions
+		errorText string
+	}{
+		{name: "missing receiver", opts: B2BOptions{Amount: 10, Remarks: "ok"}, errorText: "receiving shortcode"},
+		{name: "unknown receiver type", opts: B2BOptions{Receiver: "600000", ReceiverType: "bank", Amount: 10, AccountReference: "A", Remarks: "ok"}, errorText: "receiver type"},
+		{name: "paybill without account", opts: B2BOptions{Receiver: "600000", Amount: 10, Remarks: "ok"}, errorText: "account reference is required"},
+		{name: "long account", opts: B2BOptions{Receiver: "600000", Amount: 10, AccountReference: "ABCDEFGHIJKLMN", Remarks: "ok"}, errorText: "at most 13"},
+		{name: "unknown command", opts: B2BOptions{Receiver: "600000", Amount: 10, CommandID: "Gift", Remarks: "ok"}, errorText: "unknown B2B command"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.B2BPayment(tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.errorText) {
+				t.Errorf("expected error containing %q, got %v", tt.errorText, err)
+			}
+		})
+	}
+}
+
+// TestB2CTopUp tests that top-ups are B2B payments with BusinessPayToBulk
+func TestB2CTopUp(t *testing.T) {
+	var received b2bRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mpesa/b2b/v1/paymentrequest" {
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"ConversationID":"AG_20240706_8010","OriginatorConversationID":"5118-111210482-1","ResponseCode":"0","ResponseDescription":"Accept the service request successfully."}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(testConfig(), WithBaseURL(server.URL), WithTokenSource(StaticToken("token")))
+
+	_, err := client.B2CTopUp(B2BOptions{Receiver: "600000", ReceiverType: ReceiverTill, Amount: 50000, Remarks: "Load B2C float"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if received.CommandID != BusinessPayToBulk || received.RecieverIdentifierType != IdentifierShortcode || received.PartyB != "600000" {
+		t.Errorf("unexpected request %+v", received)
+	}
+}
@@ -0,0 +1,195 @@
+package mpesa
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// Command IDs of Business to Customer (B2C) payments
+const (
+	// SalaryPayment pays salaries, including to unregistered customers
+	SalaryPayment = "SalaryPayment"
+
+	// BusinessPayment is a normal business to customer payment, e.g. a refund
+	BusinessPayment = "BusinessPayment"
+
+	// PromotionPayment pays out promotions and bonuses
+	PromotionPayment = "PromotionPayment"
+
+	// BusinessPayToPochi pays into a customer's Pochi la Biashara account
+	BusinessPayToPochi = "BusinessPayToPochi"
+)
+
+const (
+	b2cPaymentPath     = "/mpesa/b2c/v3/paymentrequest"
+	b2pochiPaymentPath = "/mpesa/b2pochi/v1/paymentrequest"
+
+	// Field limits enforced by the B2C API
+	minRemarksLength = 2
+	maxRemarksLength = 100
+)
+
+// B2COptions describes a payment from the business shortcode to a customer.
+type B2COptions struct {
+	// PhoneNumber is the customer's phone number, in any form accepted by NormalizePhoneNumber
+	PhoneNumber string
+
+	// Amount is the amount to pay in whole shillings
+	Amount int
+
+	// CommandID is SalaryPayment, BusinessPayment (the default) or PromotionPayment
+	CommandID string
+
+	// Remarks are additional information for the payment (2 to 100 characters)
+	Remarks string
+
+	// Occasion is any additional information to be associated with the payment
+	Occasion string
+}
+
+// b2cRequest represents the JSON payload sent to the M-Pesa B2C Payment Request API.
+type b2cRequest struct {
+	// OriginatorConversationID is the unique identifier of the request chosen by the caller
+	OriginatorConversationID string `json:"OriginatorConversationID"`
+
+	// InitiatorName is the name of the user initiating the payment
+	InitiatorName string `json:"InitiatorName"`
+
+	// SecurityCredential is the encrypted credential of the initiator
+	SecurityCredential string `json:"SecurityCredential"`
+
+	// CommandID specifies the type of B2C payment
+	CommandID string `json:"CommandID"`
+
+	// Amount is the amount being paid
+	Amount int `json:"Amount"`
+
+	// PartyA is the organization's shortcode sending the funds
+	PartyA string `json:"PartyA"`
+
+	// PartyB is the phone number receiving the funds
+	PartyB string `json:"PartyB"`
+
+	// Remarks are additional information for the payment
+	Remarks string `json:"Remarks"`
+
+	// QueueTimeOutURL is the path that stores information of time out transaction
+	QueueTimeOutURL string `json:"QueueTimeOutURL"`
+
+	// ResultURL is the path that receives the result of the payment
+	ResultURL string `json:"ResultURL"`
+
+	// Occasion is any additional information to be associated with the payment
+	Occasion string `json:"Occasion"`
+}
+
+// AsyncResponse represents the acknowledgement returned by the M-Pesa APIs that
+// deliver their actual result to the ResultURL later, such as B2C payments.
+type AsyncResponse struct {
+	// ConversationID is the unique identifier M-Pesa assigned to the request
+	ConversationID string `json:"ConversationID"`
+
+	// OriginatorConversationID is the unique identifier of the request from the originator
+	OriginatorConversationID string `json:"OriginatorConversationID"`
+
+	// ResponseCode indicates the status of the request (0 for success)
+	ResponseCode string `json:"ResponseCode"`
+
+	// ResponseDescription provides a human-readable description of the response
+	ResponseDescription string `json:"ResponseDescription"`
+}
+
+// B2CPayment pays a customer from the business shortcode using the initiator,
+// security credential and callback URLs from the client's config. The outcome
+// of the payment is delivered to the ResultURL.
+func (c *Client) B2CPayment(opts B2COptions) (*AsyncResponse, error) {
+	commandID := opts.CommandID
+	if commandID == "" {
+		commandID = BusinessPayment
+	}
+	switch commandID {
+	case SalaryPayment, BusinessPayment, PromotionPayment:
+	default:
+		return nil, fmt.Errorf("command ID must be %s, %s or %s, got: %s", SalaryPayment, BusinessPayment, PromotionPayment, commandID)
+	}
+
+	return c.customerPayment(b2cPaymentPath, "B2C payments", commandID, opts)
+}
+
+// B2PochiPayment pays into a customer's Pochi la Biashara account the same way as
+// B2CPayment. The CommandID of opts is ignored.
+func (c *Client) B2PochiPayment(opts B2COptions) (*AsyncResponse, error) {
+	return c.customerPayment(b2pochiPaymentPath, "Pochi payments", BusinessPayToPochi, opts)
+}
+
+// customerPayment validates opts and sends a payment to a customer's phone number to path.
+func (c *Client) customerPayment(path, api, commandID string, opts B2COptions) (*AsyncResponse, error) {
+	if err := c.requireInitiator(api); err != nil {
+		return nil, err
+	}
+
+	phone, err := NormalizePhoneNumber(opts.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Amount < 1 {
+		return nil, fmt.Errorf("amount must be at least 1, got %d", opts.Amount)
+	}
+	if len(opts.Remarks) < minRemarksLength || len(opts.Remarks) > maxRemarksLength {
+		return nil, fmt.Errorf("remarks must be %d to %d characters", minRemarksLength, maxRemarksLength)
+	}
+
+	originatorConversationID, err := newOriginatorConversationID()
+	if err != nil {
+		return nil, err
+	}
+
+	reqBody := b2cRequest{
+		OriginatorConversationID: originatorConversationID,
+		InitiatorName:            c.config.Initiator,
+		SecurityCredential:       c.config.SecurityCredential,
+		CommandID:                commandID,
+		Amount:                   opts.Amount,
+		PartyA:                   c.config.BusinessShortcode,
+		PartyB:                   phone,
+		Remarks:                  opts.Remarks,
+		QueueTimeOutURL:          c.config.QueueTimeOutURL,
+		ResultURL:                c.config.ResultURL,
+		Occasion:                 opts.Occasion,
+	}
+
+	var result AsyncResponse
+	if err := c.postJSON(path, reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// requireInitiator checks that the config has everything the initiator-based APIs
+// (transaction status, B2C, B2B and friends) need before a request is sent.
+func (c *Client) requireInitiator(api string) error {
+	required := []struct{ key, value string }{
+		{"business_shortcode", c.config.BusinessShortcode},
+		{"initiator", c.config.Initiator},
+		{"security_credential", c.config.SecurityCredential},
+		{"result_url", c.config.ResultURL},
+		{"queue_timeout_url", c.config.QueueTimeOutURL},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			return fmt.Errorf("%s is required for %s", r.key, api)
+		}
+	}
+	return nil
+}
+
+// newOriginatorConversationID returns a random identifier for a request.
+func newOriginatorConversationID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate conversation ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,106 @@
+package mpesa
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestB2CPayment tests the B2C Payment Request payload built from the config
+func TestB2CPayment(t *testing.T) {
+	var received b2cRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mpesa/b2c/v3/paymentrequest" {
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"ConversationID":"AG_20240706_2010","OriginatorConversationID":"` + received.OriginatorConversationID + `","ResponseCode":"0","ResponseDescription":"Accept the service request successfully."}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(testConfig(), WithBaseURL(server.URL), WithTokenSource(StaticToken("token")))
+
+	result, err := client.B2CPayment(B2COptions{PhoneNumber: "0708374149", Amount: 500, CommandID: SalaryPayment, Remarks: "July salary"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.ConversationID != "AG_20240706_2010" || result.OriginatorConversationID == "" {
+		t.Errorf("unexpected response %+v", result)
+	}
+
+	config := testConfig()
+	if received.InitiatorName != config.Initiator || received.SecurityCredential != config.SecurityCredential ||
+		received.ResultURL != config.ResultURL || received.QueueTimeOutURL != config.QueueTimeOutURL {
+		t.Errorf("expected initiator and URLs from config, got %+v", received)
+	}
+	if received.PartyA != "600986" || received.PartyB != "254708374149" || received.CommandID != SalaryPayment || received.Amount != 500 {
+		t.Errorf("unexpected request %+v", received)
+	}
+}
+
+// TestB2CPaymentValidation tests that invalid payments are rejected before anything is sent
+func TestB2CPaymentValidation(t *testing.T) {
+	client := NewClient(testConfig(), WithBaseURL("http://127.0.0.1:0"), WithTokenSource(StaticToken("token")))
+
+	tests := []struct {
+		name      string
+		opts      B2COptions
+		errorText string
+	}{
+		{name: "invalid phone", opts: B2COptions{PhoneNumber: "12345", Amount: 10, Remarks: "ok"}, errorText: "phone"},
+		{name: "zero amount", opts: B2COptions{PhoneNumber: "0708374149", Remarks: "ok"}, errorText: "amount"},
+		{name: "short remarks", opts: B2COptions{PhoneNumber: "0708374149", Amount: 10, Remarks: "x"}, errorText: "remarks"},
+		{name: "unknown command", opts: B2COptions{PhoneNumber: "0708374149", Amount: 10, Remarks: "ok", CommandID: "Refund"}, errorText: "command ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.B2CPayment(tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.errorText) {
+				t.Errorf("expected error containing %q, got %v", tt.errorText, err)
+			}
+		})
+	}
+
+	noInitiator := testConfig()
+	noInitiator.Initiator = ""
+	client = NewClient(noInitiator, WithTokenSource(StaticToken("token")))
+	_, err := client.B2CPayment(B2COptions{PhoneNumber: "0708374149", Amount: 10, Remarks: "ok"})
+	if err == nil || !strings.Contains(err.Error(), "initiator is required") {
+		t.Errorf("expected missing initiator error, got %v", err)
+	}
+}
+
+// TestB2PochiPayment tests that Pochi payments use their own endpoint and command ID
+func TestB2PochiPayment(t *testing.T) {
+	var received b2cRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mpesa/b2pochi/v1/paymentrequest" {
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"ConversationID":"AG_20240706_7010","OriginatorConversationID":"` + received.OriginatorConversationID + `","ResponseCode":"0","ResponseDescription":"Accept the service request successfully."}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(testConfig(), WithBaseURL(server.URL), WithTokenSource(StaticToken("token")))
+
+	result, err := client.B2PochiPayment(B2COptions{PhoneNumber: "0708374149", Amount: 250, CommandID: SalaryPayment, Remarks: "Supplier"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.ConversationID != "AG_20240706_7010" {
+		t.Errorf("unexpected response %+v", result)
+	}
+	if received.CommandID != BusinessPayToPochi || received.PartyB != "254708374149" || received.InitiatorName != "testapi" {
+		t.Errorf("unexpected request %+v", received)
+	}
+}
@@ -0,0 +1,123 @@
+package mpesa
+
+import (
+	"fmt"
+	"strings"
+)
+
+const accountBalancePath = "/mpesa/accountbalance/v1/query"
+
+// accountBalanceRequest represents the JSON payload sent to the M-Pesa Account Balance API.
+type accountBalanceRequest struct {
+	// Initiator is the name of the user initiating the request
+	Initiator string `json:"Initiator"`
+
+	// SecurityCredential is the encrypted credential of the initiator
+	SecurityCredential string `json:"SecurityCredential"`
+
+	// CommandID is always AccountBalance
+	CommandID string `json:"CommandID"`
+
+	// PartyA is the organization's shortcode whose balance is queried
+	PartyA string `json:"PartyA"`
+
+	// IdentifierType specifies the type of PartyA
+	IdentifierType string `json:"IdentifierType"`
+
+	// Remarks are additional information for the request
+	Remarks string `json:"Remarks"`
+
+	// QueueTimeOutURL is the path that stores information of time out transaction
+	QueueTimeOutURL string `json:"QueueTimeOutURL"`
+
+	// ResultURL is the path that receives the balance
+	ResultURL string `json:"ResultURL"`
+}
+
+// AccountBalance is the balance of one account of a shortcode. Amounts are kept
+// exactly as reported by M-Pesa.
+type AccountBalance struct {
+	// Name is the account name, e.g. "Working Account"
+	Name string
+
+	// Currency is the account currency, e.g. "KES"
+	Currency string
+
+	// Current is the total amount in the account
+	Current string
+
+	// Available is the amount that can be transacted
+	Available string
+
+	// Reserved is the amount held back, e.g. for pending transactions
+	Reserved string
+
+	// Uncleared is the amount not yet cleared
+	Uncleared string
+}
+
+// AccountBalance requests the balance of the business shortcode. The balance is
+// delivered to the ResultURL; use ParseAccountBalance on its AccountBalance parameter.
+func (c *Client) AccountBalance() (*AsyncResponse, error) {
+	if err := c.requireInitiator("account balance queries"); err != nil {
+		return nil, err
+	}
+
+	reqBody := accountBalanceRequest{
+		Initiator:          c.config.Initiator,
+		SecurityCredential: c.config.SecurityCredential,
+		CommandID:          "AccountBalance",
+		PartyA:             c.config.BusinessShortcode,
+		IdentifierType:     IdentifierShortcode,
+		Remarks:            "Balance Check",
+		QueueTimeOutURL:    c.config.QueueTimeOutURL,
+		ResultURL:          c.config.ResultURL,
+	}
+
+	var result AsyncResponse
+	if err := c.postJSON(accountBalancePath, reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// ParseAccountBalance parses the AccountBalance result parameter, a list of accounts
+// separated by '&' whose fields are separated by '|':
+//
+//	Working Account|KES|46713.00|46713.00|0.00|0.00&Utility Account|KES|0.00|0.00|0.00|0.00
+//
+// The fields are the account name, currency, current, available, reserved and uncleared amounts.
+func ParseAccountBalance(value string) ([]AccountBalance, error) {
+	var balances []AccountBalance
+
+	for _, account := range strings.Split(value, "&") {
+		account = strings.TrimSpace(account)
+		if account == "" {
+			continue
+		}
+
+		fields := strings.Split(account, "|")
+		if len(fields) < 5 {
+			return nil, fmt.Errorf("invalid account balance entry: %s", account)
+		}
+
+		balance := AccountBalance{
+			Name:      fields[0],
+			Currency:  fields[1],
+			Current:   fields[2],
+			Available: fields[3],
+			Reserved:  fields[4],
+		}
+		if len(fields) > 5 {
+			balance.Uncleared = fields[5]
+		}
+		balances = append(balances, balance)
+	}
+
+	if len(balances) == 0 {
+		return nil, fmt.Errorf("no accounts in account balance: %q", value)
+	}
+
+	return balances, nil
+}
@@ -0,0 +1,61 @@
+package mpesa
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestAccountBalance tests the Account Balance request built from the config
+func TestAccountBalance(t *testing.T) {
+	var received accountBalanceRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mpesa/accountbalance/v1/query" {
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"ConversationID":"AG_20240706_4010","OriginatorConversationID":"5118-111210482-1","ResponseCode":"0","ResponseDescription":"Accept the service request successfully."}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(testConfig(), WithBaseURL(server.URL), WithTokenSource(StaticToken("token")))
+
+	result, err := client.AccountBalance()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.ConversationID != "AG_20240706_4010" {
+		t.Errorf("unexpected response %+v", result)
+	}
+	if received.CommandID != "AccountBalance" || received.PartyA != "600986" || received.IdentifierType != IdentifierShortcode || received.Initiator != "testapi" {
+		t.Errorf("unexpected request %+v", received)
+	}
+}
+
+// TestParseAccountBalance tests parsing the pipe-delimited AccountBalance parameter
+func TestParseAccountBalance(t *testing.T) {
+	value := "Working Account|KES|46713.00|46713.00|0.00|0.00&Float Account|KES|0.00|0.00|0.00|0.00&Utility Account|KES|150.00|120.00|30.00|0.00"
+
+	balances, err := ParseAccountBalance(value)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(balances) != 3 {
+		t.Fatalf("expected 3 accounts, got %d", len(balances))
+	}
+
+	expected := AccountBalance{Name: "Utility Account", Currency: "KES", Current: "150.00", Available: "120.00", Reserved: "30.00", Uncleared: "0.00"}
+	if balances[2] != expected {
+		t.Errorf("expected %+v, got %+v", expected, balances[2])
+	}
+
+	for _, invalid := range []string{"", "Working Account|KES|1.00"} {
+		if _, err := ParseAccountBalance(invalid); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package mpesa
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	billOptInPath          = "/v1/billmanager-invoice/optin"
+	billSingleInvoicePath  = "/v1/billmanager-invoice/single-invoicing"
+	billBulkInvoicePath    = "/v1/billmanager-invoice/bulk-invoicing"
+	billCancelSinglePath   = "/v1/billmanager-invoice/cancel-single-invoice"
+	billCancelBulkPath     = "/v1/billmanager-invoice/cancel-bulk-invoices"
+	billReconciliationPath = "/v1/billmanager-invoice/reconciliation"
+
+	// billSuccessCode is the rescode of successful Bill Manager requests
+	billSuccessCode = "200"
+
+	// invoiceDateLayout is the date format of invoice due dates
+	invoiceDateLayout = "2006-01-02"
+
+	// MaxInvoicesPerRequest is the number of invoices the bulk invoicing API accepts per request
+	MaxInvoicesPerRequest = 1000
+)
+
+// BillOptInOptions describes the onboarding of the business shortcode to Bill Manager.
+type BillOptInOptions struct {
+	// Email is the business email address shown on invoices
+	Email string
+
+	// OfficialContact is the business phone number shown on invoices
+	OfficialContact string
+
+	// SendReminders enables SMS reminders for unpaid invoices
+	SendReminders bool
+
+	// Logo is the URL of the business logo shown on invoices
+	Logo string
+
+	// CallbackURL receives payment notifications; it defaults to the configured CallbackURL
+	CallbackURL string
+}
+
+// billOptInRequest represents the JSON payload sent to the Bill Manager opt-in API.
+type billOptInRequest struct {
+	// ShortCode is the organization's shortcode (Paybill)
+	ShortCode string `json:"shortcode"`
+
+	// Email is the business email address
+	Email string `json:"email"`
+
+	// OfficialContact is the business phone number
+	OfficialContact string `json:"officialContact"`
+
+	// SendReminders is "1" to send SMS reminders and "0" not to
+	SendReminders string `json:"sendReminders"`
+
+	// Logo is the URL of the business logo
+	Logo string `json:"logo"`
+
+	// CallbackURL receives payment notifications
+	CallbackURL string `json:"callbackurl"`
+}
+
+// InvoiceItem is an additional line on an invoice.
+type InvoiceItem struct {
+	// ItemName describes the line, e.g. "Transport"
+	ItemName string `json:"itemName"`
+
+	// Amount is the amount of the line in whole shillings
+	Amount int `json:"amount,string"`
+}
+
+// Invoice is a Bill Manager invoice sent to a customer. It is sent to the API as is.
+type Invoice struct {
+	// ExternalReference is the unique identifier of the invoice in your system
+	ExternalReference string `json:"externalReference"`
+
+	// BilledFullName is the name of the customer
+	BilledFullName string `json:"billedFullName"`
+
+	// BilledPhoneNumber is the customer's phone number, in any form accepted by NormalizePhoneNumber
+	BilledPhoneNumber string `json:"billedPhoneNumber"`
+
+	// BilledPeriod is the period the invoice is for, e.g. "August 2021"
+	BilledPeriod string `json:"billedPeriod"`
+
+	// InvoiceName is a descriptive name of the invoice, e.g. "School Fees"
+	InvoiceName string `json:"invoiceName"`
+
+	// DueDate is the date the invoice is due, as YYYY-MM-DD
+	DueDate string `json:"dueDate"`
+
+	// AccountReference is the account number customers pay the invoice to
+	AccountReference string `json:"accountReference"`
+
+	// Amount is the total amount of the invoice in whole shillings
+	Amount int `json:"amount,string"`
+
+	// InvoiceItems are optional additional lines on the invoice
+	InvoiceItems []InvoiceItem `json:"invoiceItems,omitempty"`
+}
+
+// billResponse represents the JSON response from the Bill Manager APIs.
+type billResponse struct {
+	// AppKey is returned by the opt-in API and identifies the onboarded shortcode
+	AppKey string `json:"app_key,omitempty"`
+
+	// ResMsg provides a human-readable description of the response
+	ResMsg string `json:"resmsg"`
+
+	// ResCode indicates the status of the request ("200" for success)
+	ResCode string `json:"rescode"`
+
+	// StatusMessage provides details of invoicing and cancellation requests
+	StatusMessage string `json:"Status_Message,omitempty"`
+}
+
+// BillPayment is the notification Bill Manager posts to the opt-in callback URL
+// when a customer pays an invoice.
+type BillPayment struct {
+	// TransactionID is the M-Pesa receipt number of the payment
+	TransactionID string `json:"transactionId"`
+
+	// PaidAmount is the amount paid
+	PaidAmount string `json:"paidAmount"`
+
+	// MSISDN is the phone number of the paying customer
+	MSISDN string `json:"msisdn"`
+
+	// DateCreated is the date of the payment
+	DateCreated string `json:"dateCreated"`
+
+	// AccountReference is the account number the customer paid to
+	AccountReference string `json:"accountReference"`
+
+	// ShortCode is the shortcode that received the payment
+	ShortCode string `json:"shortCode"`
+}
+
+// billReconciliationRequest represents the JSON payload sent to the Bill Manager reconciliation API.
+// The payment fields are copied from the BillPayment notification.
+type billReconciliationRequest struct {
+	PaymentDate      string `json:"paymentDate"`
+	PaidAmount       string `json:"paidAmount"`
+	AccountReference string `json:"accountReference"`
+	TransactionID    string `json:"transactionId"`
+	PhoneNumber      string `json:"phoneNumber"`
+
+	// FullName, InvoiceName and ExternalReference identify the paid invoice
+	FullName          string `json:"fullName"`
+	InvoiceName       string `json:"invoiceName"`
+	ExternalReference string `json:"externalReference"`
+}
+
+// BillOptIn onboards the business shortcode to Bill Manager.
+func (c *Client) BillOptIn(opts BillOptInOptions) (*billResponse, error) {
+	if c.config.BusinessShortcode == "" {
+		return nil, fmt.Errorf("business_shortcode is required to opt in to Bill Manager")
+	}
+	if opts.Email == "" || opts.OfficialContact == "" {
+		return nil, fmt.Errorf("an email address and official contact are required to opt in to Bill Manager")
+	}
+
+	contact, err := NormalizePhoneNumber(opts.OfficialContact)
+	if err != nil {
+		return nil, err
+	}
+
+	callbackURL := opts.CallbackURL
+	if callbackURL == "" {
+		callbackURL = c.config.CallbackURL
+	}
+	if err := ValidateCallbackURL(callbackURL, c.config.Environment); err != nil {
+		return nil, err
+	}
+
+	sendReminders := "0"
+	if opts.SendReminders {
+		sendReminders = "1"
+	}
+
+	reqBody := billOptInRequest{
+		ShortCode:       c.config.BusinessShortcode,
+		Email:           opts.Email,
+		OfficialContact: contact,
+		SendReminders:   sendReminders,
+		Logo:            opts.Logo,
+		CallbackURL:     callbackURL,
+	}
+
+	return c.postBill(billOptInPath, reqBody)
+}
+
+// SendInvoice sends a single invoice to a customer.
+func (c *Client) SendInvoice(invoice Invoice) (*billResponse, error) {
+	if err := normalizeInvoice(&invoice); err != nil {
+		return nil, err
+	}
+	return c.postBill(billSingleInvoicePath, invoice)
+}
+
+// SendInvoices sends invoices in bulk, split into requests of at most
+// MaxInvoicesPerRequest invoices. All invoices are validated before the first
+// request. On error, the responses of the requests that succeeded are returned
+// along with it.
+func (c *Client) SendInvoices(invoices []Invoice) ([]*billResponse, error) {
+	invoices = append([]Invoice(nil), invoices...)
+	for i := range invoices {
+		if err := normalizeInvoice(&invoices[i]); err != nil {
+			return nil, fmt.Errorf("invoice %d: %w", i+1, err)
+		}
+	}
+
+	var responses []*billResponse
+	for start := 0; start < len(invoices); start += MaxInvoicesPerRequest {
+		end := min(start+MaxInvoicesPerRequest, len(invoices))
+
+		result, err := c.postBill(billBulkInvoicePath, invoices[start:end])
+		if err != nil {
+			return responses, fmt.Errorf("invoices %d to %d: %w", start+1, end, err)
+		}
+		responses = append(responses, result)
+	}
+
+	return responses, nil
+}
+
+// CancelInvoices cancels the invoices with the given external references.
+func (c *Client) CancelInvoices(externalReferences ...string) (*billResponse, error) {
+	if len(externalReferences) == 0 {
+		return nil, fmt.Errorf("at least one invoice reference is required")
+	}
+
+	type cancelRequest struct {
+		ExternalReference string `json:"externalReference"`
+	}
+
+	if len(externalReferences) == 1 {
+		return c.postBill(billCancelSinglePath, cancelRequest{ExternalReference: externalReferences[0]})
+	}
+
+	reqBody := make([]cancelRequest, len(externalReferences))
+	for i, ref := range externalReferences {
+		reqBody[i] = cancelRequest{ExternalReference: ref}
+	}
+	return c.postBill(billCancelBulkPath, reqBody)
+}
+
+// ReconcileBillPayment acknowledges a paid invoice, which sends the customer an
+// e-receipt. invoice supplies the names and external reference of the paid invoice
+// and may be nil if they are not known.
+func (c *Client) ReconcileBillPayment(payment BillPayment, invoice *Invoice) (*billResponse, error) {
+	reqBody := billReconciliationRequest{
+		PaymentDate:      payment.DateCreated,
+		PaidAmount:       payment.PaidAmount,
+		AccountReference: payment.AccountReference,
+		TransactionID:    payment.TransactionID,
+		PhoneNumber:      payment.MSISDN,
+	}
+	if invoice != nil {
+		reqBody.FullName = invoice.BilledFullName
+		reqBody.InvoiceName = invoice.InvoiceName
+		reqBody.ExternalReference = invoice.ExternalReference
+	}
+
+	return c.postBill(billReconciliationPath, reqBody)
+}
+
+// NewBillPaymentHandler returns an http.Handler for the opt-in callback URL. It
+// acknowledges each payment notification and reconciles the payment, looking up
+// the paid invoice by account reference with lookup (which may be nil). Each
+// payment and the outcome of its reconciliation are passed to report.
+func (c *Client) NewBillPaymentHandler(lookup func(accountReference string) *Invoice, report func(BillPayment, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var payment BillPayment
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxResultBodySize)).Decode(&payment); err != nil {
+			http.Error(w, "invalid payment notification", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"resmsg":"Success","rescode":"200"}`))
+
+		var invoice *Invoice
+		if lookup != nil {
+			invoice = lookup(payment.AccountReference)
+		}
+		_, err := c.ReconcileBillPayment(payment, invoice)
+		report(payment, err)
+	})
+}
+
+// postBill sends a Bill Manager request and turns unsuccessful rescodes into errors.
+func (c *Client) postBill(path string, payload interface{}) (*billResponse, error) {
+	var result billResponse
+	if err := c.postJSON(path, payload, &result); err != nil {
+		return nil, err
+	}
+	if result.ResCode != billSuccessCode {
+		return nil, fmt.Errorf("bill manager request failed with code %s: %s", result.ResCode, result.ResMsg)
+	}
+	return &result, nil
+}
+
+// normalizeInvoice checks the required fields of invoice and normalizes its phone number.
+func normalizeInvoice(invoice *Invoice) error {
+	required := []struct{ name, value string }{
+		{"external reference", invoice.ExternalReference},
+		{"billed full name", invoice.BilledFullName},
+		{"billed period", invoice.BilledPeriod},
+		{"invoice name", invoice.InvoiceName},
+		{"due date", invoice.DueDate},
+		{"account reference", invoice.AccountReference},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			return fmt.Errorf("the %s is required", r.name)
+		}
+	}
+	if invoice.Amount < 1 {
+		return fmt.Errorf("amount must be at least 1, got %d", invoice.Amount)
+	}
+	if _, err := time.Parse(invoiceDateLayout, invoice.DueDate); err != nil {
+		return fmt.Errorf("invalid due date '%s': use YYYY-MM-DD", invoice.DueDate)
+	}
+
+	phone, err := NormalizePhoneNumber(invoice.BilledPhoneNumber)
+	if err != nil {
+		return err
+	}
+	invoice.BilledPhoneNumber = phone
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package mpesa
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func testInvoice(ref string) Invoice {
+	return Invoice{
+		ExternalReference: ref,
+		BilledFullName:    "John Doe",
+		BilledPhoneNumber: "0722000000",
+		BilledPeriod:      "August 2021",
+		InvoiceName:       "School Fees",
+		DueDate:           "2021-10-12",
+		AccountReference:  "ACC-" + ref,
+		Amount:            800,
+	}
+}
+
+// TestSendInvoice tests the single invoice payload and rescode handling
+func TestSendInvoice(t *testing.T) {
+	var received map[string]interface{}
+	rescode := "200"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/billmanager-invoice/single-invoicing" {
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		_, _ = fmt.Fprintf(w, `{"Status_Message":"Invoice sent successfully","resmsg":"Success","rescode":"%s"}`, rescode)
+	}))
+	defer server.Close()
+
+	client := NewClient(GetDefaultConfig(), WithBaseURL(server.URL), WithTokenSource(StaticToken("token")))
+
+	invoice := testInvoice("955")
+	invoice.InvoiceItems = []InvoiceItem{{ItemName: "Transport", Amount: 200}}
+
+	result, err := client.SendInvoice(invoice)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.StatusMessage != "Invoice sent successfully" {
+		t.Errorf("unexpected response %+v", result)
+	}
+	if received["billedPhoneNumber"] != "254722000000" || received["amount"] != "800" {
+		t.Errorf("expected normalized phone and string amount, got %v", received)
+	}
+
+	rescode = "400"
+	if _, err := client.SendInvoice(invoice); err == nil || !strings.Contains(err.Error(), "code 400") {
+		t.Errorf("expected rescode error, got %v", err)
+	}
+
+	invoice.DueDate = "12/10/2021"
+	if _, err := client.SendInvoice(invoice); err == nil || !strings.Contains(err.Error(), "due date") {
+		t.Errorf("expected due date error, got %v", err)
+	}
+}
+
+// TestSendInvoicesChunks tests that bulk invoices are split at MaxInvoicesPerRequest
+func TestSendInvoicesChunks(t *testing.T) {
+	var batches []int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/billmanager-invoice/bulk-invoicing" {
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+		var invoices []Invoice
+		_ = json.NewDecoder(r.Body).Decode(&invoices)
+		batches = append(batches, len(invoices))
+		_, _ = w.Write([]byte(`{"Status_Message":"Invoices sent successfully","resmsg":"Success","rescode":"200"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(GetDefaultConfig(), WithBaseURL(server.URL), WithTokenSource(StaticToken("token")))
+
+	invoices := make([]Invoice, MaxInvoicesPerRequest+1)
+	for i := range invoices {
+		invoices[i] = testInvoice(fmt.Sprint(i))
+	}
+
+	results, err := client.SendInvoices(invoices)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(results) != 2 || len(batches) != 2 || batches[0] != MaxInvoicesPerRequest || batches[1] != 1 {
+		t.Errorf("expected batches of %d and 1, got %v", MaxInvoicesPerRequest, batches)
+	}
+
+	invoices[5].AccountReference = ""
+	batches = nil
+	if _, err := client.SendInvoices(invoices); err == nil || !strings.Contains(err.Error(), "invoice 6") {
+		t.Errorf("expected invoice 6 to be rejected, got %v", err)
+	}
+	if len(batches) != 0 {
+		t.Error("expected nothing to be sent when an invoice is invalid")
+	}
+}
+
+// TestBillPaymentHandler tests acknowledging and reconciling payment notifications
+func TestBillPaymentHandler(t *testing.T) {
+	var received billReconciliationRequest
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/billmanager-invoice/reconciliation" {
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		_, _ = w.Write([]byte(`{"resmsg":"Success","rescode":"200"}`))
+	}))
+	defer api.Close()
+
+	client := NewClient(GetDefaultConfig(), WithBaseURL(api.URL), WithTokenSource(StaticToken("token")))
+
+	invoice := testInvoice("955")
+	lookup := func(accountReference string) *Invoice {
+		if accountReference == invoice.AccountReference {
+			return &invoice
+		}
+		return nil
+	}
+	var reported []BillPayment
+	report := func(payment BillPayment, err error) {
+		if err != nil {
+			t.Errorf("expected reconciliation to succeed, got %v", err)
+		}
+		reported = append(reported, payment)
+	}
+
+	handler := client.NewBillPaymentHandler(lookup, report)
+
+	body := `{"transactionId":"RJB53MYR1N","paidAmount":"800","msisdn":"254722000000","dateCreated":"2021-09-15","accountReference":"ACC-955","shortCode":"600986"}`
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"rescode":"200"`) {
+		t.Errorf("expected acknowledgement, got %d %s", recorder.Code, recorder.Body.String())
+	}
+	if len(reported) != 1 || reported[0].TransactionID != "RJB53MYR1N" {
+		t.Errorf("expected payment to be reported, got %+v", reported)
+	}
+	if received.ExternalReference != "955" || received.FullName != "John Doe" || received.TransactionID != "RJB53MYR1N" {
+		t.Errorf("unexpected reconciliation request %+v", received)
+	}
+}
@@ -0,0 +1,293 @@
+package mpesa
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	c2bRegisterPath = "/mpesa/c2b/v1/registerurl"
+	c2bSimulatePath = "/mpesa/c2b/v1/simulate"
+)
+
+// Response types of C2B URL registration: what M-Pesa does with a payment when
+// the validation URL cannot be reached
+const (
+	ResponseTypeCompleted = "Completed"
+	ResponseTypeCancelled = "Cancelled"
+)
+
+// blockedURLKeywords are words that Daraja silently rejects in callback URLs
+var blockedURLKeywords = []string{"m-pesa", "mpesa", "safaricom", "exec", "exe", "cmd", "sql", "query"}
+
+// C2BRegisterOptions describes the URLs to register for C2B payments to the business shortcode.
+type C2BRegisterOptions struct {
+	// ConfirmationURL receives a notification for every completed payment
+	ConfirmationURL string
+
+	// ValidationURL is asked to accept or reject payments, if external validation is enabled
+	ValidationURL string
+
+	// ResponseType is ResponseTypeCompleted (the default) or ResponseTypeCancelled
+	ResponseType string
+}
+
+// c2bRegisterRequest represents the JSON payload sent to the M-Pesa C2B Register URL API.
+type c2bRegisterRequest struct {
+	// ShortCode is the organization's shortcode (Paybill or Buygoods)
+	ShortCode string `json:"ShortCode"`
+
+	// ResponseType is the default action when the validation URL is unreachable
+	ResponseType string `json:"ResponseType"`
+
+	// ConfirmationURL receives payment confirmations
+	ConfirmationURL string `json:"ConfirmationURL"`
+
+	// ValidationURL receives payment validation requests
+	ValidationURL string `json:"ValidationURL"`
+}
+
+// C2BSimulateOptions describes a simulated customer payment to the business shortcode.
+type C2BSimulateOptions struct {
+	// Amount is the amount paid in whole shillings
+	Amount int
+
+	// MSISDN is the paying customer's phone number, in any form accepted by NormalizePhoneNumber
+	MSISDN string
+
+	// BillRefNumber is the account number entered by the customer (Paybill only)
+	BillRefNumber string
+
+	// CommandID is CustomerPayBillOnline (the default) or CustomerBuyGoodsOnline
+	CommandID string
+}
+
+// c2bSimulateRequest represents the JSON payload sent to the M-Pesa C2B Simulate API.
+type c2bSimulateRequest struct {
+	// ShortCode is the organization's shortcode receiving the payment
+	ShortCode string `json:"ShortCode"`
+
+	// CommandID is CustomerPayBillOnline or CustomerBuyGoodsOnline
+	CommandID string `json:"CommandID"`
+
+	// Amount is the amount being paid
+	Amount int `json:"Amount"`
+
+	// Msisdn is the phone number making the payment
+	Msisdn string `json:"Msisdn"`
+
+	// BillRefNumber is the account reference of a Paybill payment
+	BillRefNumber string `json:"BillRefNumber,omitempty"`
+}
+
+// c2bResponse represents the JSON response from the M-Pesa C2B APIs.
+type c2bResponse struct {
+	// OriginatorConversationID is the unique identifier of the request (sic, as spelled by the API)
+	OriginatorConversationID string `json:"OriginatorCoversationID"`
+
+	// ResponseCode indicates the status of the request (0 for success)
+	ResponseCode string `json:"ResponseCode"`
+
+	// ResponseDescription provides a human-readable description of the response
+	ResponseDescription string `json:"ResponseDescription"`
+}
+
+// ValidateCallbackURL checks rawURL against the rules Daraja applies to callback URLs:
+// it must be an absolute http(s) URL, https in production, and must not contain any
+// keyword Daraja rejects (such as "mpesa", "safaricom" or "sql").
+func ValidateCallbackURL(rawURL, environment string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid URL '%s': must be an absolute URL", rawURL)
+	}
+
+	switch u.Scheme {
+	case "https":
+	case "http":
+		if environment == "production" {
+			return fmt.Errorf("invalid URL '%s': production requires https", rawURL)
+		}
+	default:
+		return fmt.Errorf("invalid URL '%s': scheme must be http or https", rawURL)
+	}
+
+	lower := strings.ToLower(rawURL)
+	for _, keyword := range blockedURLKeywords {
+		if strings.Contains(lower, keyword) {
+			return fmt.Errorf("invalid URL '%s': Daraja rejects URLs containing '%s'", rawURL, keyword)
+		}
+	}
+
+	return nil
+}
+
+// RegisterC2BURLs registers the confirmation and validation URLs of the business shortcode.
+// Both URLs are checked with ValidateCallbackURL before anything is sent.
+func (c *Client) RegisterC2BURLs(opts C2BRegisterOptions) (*c2bResponse, error) {
+	if c.config.BusinessShortcode == "" {
+		return nil, fmt.Errorf("business_shortcode is required to register C2B URLs")
+	}
+
+	responseType := opts.ResponseType
+	if responseType == "" {
+		responseType = ResponseTypeCompleted
+	}
+	if responseType != ResponseTypeCompleted && responseType != ResponseTypeCancelled {
+		return nil, fmt.Errorf("response type must be %s or %s, got: %s", ResponseTypeCompleted, ResponseTypeCancelled, responseType)
+	}
+
+	for _, u := range []string{opts.ConfirmationURL, opts.ValidationURL} {
+		if err := ValidateCallbackURL(u, c.config.Environment); err != nil {
+			return nil, err
+		}
+	}
+
+	reqBody := c2bRegisterRequest{
+		ShortCode:       c.config.BusinessShortcode,
+		ResponseType:    responseType,
+		ConfirmationURL: opts.ConfirmationURL,
+		ValidationURL:   opts.ValidationURL,
+	}
+
+	var result c2bResponse
+	if err := c.postJSON(c2bRegisterPath, reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// SimulateC2B fires a simulated customer payment into the business shortcode.
+// Simulation only exists in the sandbox, so it is refused for production configurations.
+func (c *Client) SimulateC2B(opts C2BSimulateOptions) (*c2bResponse, error) {
+	if c.config.Environment == "production" {
+		return nil, fmt.Errorf("C2B simulation is only available in the sandbox environment")
+	}
+	if c.config.BusinessShortcode == "" {
+		return nil, fmt.Errorf("business_shortcode is required to simulate C2B payments")
+	}
+
+	msisdn, err := NormalizePhoneNumber(opts.MSISDN)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Amount < 1 {
+		return nil, fmt.Errorf("amount must be at least 1, got %d", opts.Amount)
+	}
+
+	commandID := opts.CommandID
+	if commandID == "" {
+		commandID = CustomerPayBillOnline
+	}
+	if commandID != CustomerPayBillOnline && commandID != CustomerBuyGoodsOnline {
+		return nil, fmt.Errorf("command ID must be %s or %s, got: %s", CustomerPayBillOnline, CustomerBuyGoodsOnline, commandID)
+	}
+
+	reqBody := c2bSimulateRequest{
+		ShortCode:     c.config.BusinessShortcode,
+		CommandID:     commandID,
+		Amount:        opts.Amount,
+		Msisdn:        msisdn,
+		BillRefNumber: opts.BillRefNumber,
+	}
+
+	var result c2bResponse
+	if err := c.postJSON(c2bSimulatePath, reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// C2BRegistration records the URLs registered for a shortcode.
+type C2BRegistration struct {
+	ShortCode       string    `json:"short_code"`
+	Environment     string    `json:"environment"`
+	ConfirmationURL string    `json:"confirmation_url"`
+	ValidationURL   string    `json:"validation_url"`
+	ResponseType    string    `json:"response_type"`
+	RegisteredAt    time.Time `json:"registered_at"`
+}
+
+// RegistrationStore keeps track of the C2B URLs registered per environment and shortcode,
+// since Daraja offers no way to look them up.
+type RegistrationStore struct {
+	path string
+}
+
+// NewRegistrationStore returns a registration store backed by the file at path.
+func NewRegistrationStore(path string) *RegistrationStore {
+	return &RegistrationStore{path: path}
+}
+
+// DefaultRegistrationsPath returns the location of the registration store in the user's config directory.
+func DefaultRegistrationsPath() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "c2b-registrations.json"), nil
+}
+
+// Save records reg, replacing any earlier registration for the same environment and shortcode.
+func (s *RegistrationStore) Save(reg C2BRegistration) error {
+	registrations, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	registrations[registrationKey(reg.Environment, reg.ShortCode)] = reg
+
+	data, err := json.MarshalIndent(registrations, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode registrations: %w", err)
+	}
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("failed to write registrations: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the registration recorded for shortcode in environment, or nil if there is none.
+func (s *RegistrationStore) Get(environment, shortcode string) (*C2BRegistration, error) {
+	registrations, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	reg, ok := registrations[registrationKey(environment, shortcode)]
+	if !ok {
+		return nil, nil
+	}
+	return &reg, nil
+}
+
+// load reads all registrations. A missing file is not an error.
+func (s *RegistrationStore) load() (map[string]C2BRegistration, error) {
+	registrations := map[string]C2BRegistration{}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return registrations, nil
+		}
+		return nil, fmt.Errorf("failed to read registrations: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &registrations); err != nil {
+		return nil, fmt.Errorf("failed to parse registrations: %w", err)
+	}
+
+	return registrations, nil
+}
+
+func registrationKey(environment, shortcode string) string {
+	return environment + ":" + shortcode
+}
@@ -0,0 +1,159 @@
+package mpesa
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestValidateCallbackURL tests the Daraja callback URL rules
+func TestValidateCallbackURL(t *testing.T) {
+	tests := []struct {
+		url         string
+		environment string
+		errorText   string
+	}{
+		{url: "https://example.com/confirmation", environment: "production"},
+		{url: "http://example.com/confirmation", environment: "sandbox"},
+		{url: "http://example.com/confirmation", environment: "production", errorText: "requires https"},
+		{url: "https://example.com/mpesa/confirmation", environment: "sandbox", errorText: "'mpesa'"},
+		{url: "https://example.com/M-PESA/confirmation", environment: "sandbox", errorText: "'m-pesa'"},
+		{url: "https://safaricom.example.com/c2b", environment: "sandbox", errorText: "'safaricom'"},
+		{url: "https://example.com/c2b?query=1", environment: "sandbox", errorText: "'query'"},
+		{url: "https://example.com/SQLhook", environment: "sandbox", errorText: "'sql'"},
+		{url: "ftp://example.com/c2b", environment: "sandbox", errorText: "scheme"},
+		{url: "/relative/path", environment: "sandbox", errorText: "absolute URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := ValidateCallbackURL(tt.url, tt.environment)
+			validateTestError(t, err, tt.errorText != "", tt.errorText)
+		})
+	}
+}
+
+// TestRegisterC2BURLs tests the C2B Register URL request
+func TestRegisterC2BURLs(t *testing.T) {
+	var received c2bRegisterRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mpesa/c2b/v1/registerurl" {
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"OriginatorCoversationID":"7619-37765134-1","ResponseCode":"0","ResponseDescription":"success"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(GetDefaultConfig(), WithBaseURL(server.URL), WithTokenSource(StaticToken("token")))
+
+	result, err := client.RegisterC2BURLs(C2BRegisterOptions{
+		ConfirmationURL: "https://example.com/confirmation",
+		ValidationURL:   "https://example.com/validation",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if result.OriginatorConversationID != "7619-37765134-1" || result.ResponseCode != "0" {
+		t.Errorf("unexpected response %+v", result)
+	}
+	if received.ShortCode != "600986" || received.ResponseType != ResponseTypeCompleted {
+		t.Errorf("unexpected request %+v", received)
+	}
+
+	_, err = client.RegisterC2BURLs(C2BRegisterOptions{
+		ConfirmationURL: "https://example.com/mpesa/confirmation",
+		ValidationURL:   "https://example.com/validation",
+	})
+	if err == nil || !strings.Contains(err.Error(), "Daraja rejects") {
+		t.Errorf("expected URL to be rejected locally, got %v", err)
+	}
+
+	_, err = client.RegisterC2BURLs(C2BRegisterOptions{
+		ConfirmationURL: "https://example.com/confirmation",
+		ValidationURL:   "https://example.com/validation",
+		ResponseType:    "Maybe",
+	})
+	if err == nil || !strings.Contains(err.Error(), "response type") {
+		t.Errorf("expected invalid response type error, got %v", err)
+	}
+}
+
+// TestRegistrationStore tests recording registered URLs per environment and shortcode
+func TestRegistrationStore(t *testing.T) {
+	store := NewRegistrationStore(filepath.Join(t.TempDir(), "c2b-registrations.json"))
+
+	reg, err := store.Get("sandbox", "600986")
+	if err != nil || reg != nil {
+		t.Fatalf("expected no registration, got %+v %v", reg, err)
+	}
+
+	saved := C2BRegistration{
+		ShortCode:       "600986",
+		Environment:     "sandbox",
+		ConfirmationURL: "https://example.com/confirmation",
+		ValidationURL:   "https://example.com/validation",
+		ResponseType:    ResponseTypeCompleted,
+		RegisteredAt:    time.Now().Round(time.Second),
+	}
+	if err := store.Save(saved); err != nil {
+		t.Fatalf("failed to save registration: %v", err)
+	}
+
+	reg, err = store.Get("sandbox", "600986")
+	if err != nil || reg == nil {
+		t.Fatalf("expected registration, got %v", err)
+	}
+	if reg.ConfirmationURL != saved.ConfirmationURL || !reg.RegisteredAt.Equal(saved.RegisteredAt) {
+		t.Errorf("expected %+v, got %+v", saved, *reg)
+	}
+
+	if reg, _ := store.Get("production", "600986"); reg != nil {
+		t.Error("expected no registration for another environment")
+	}
+}
+
+// TestSimulateC2B tests the C2B Simulate request and the production guard
+func TestSimulateC2B(t *testing.T) {
+	var received c2bSimulateRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mpesa/c2b/v1/simulate" {
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"OriginatorCoversationID":"53e3-4aa8-9fe0-8fb5e4092cdd3405976","ResponseCode":"0","ResponseDescription":"Accept the service request successfully."}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(GetDefaultConfig(), WithBaseURL(server.URL), WithTokenSource(StaticToken("token")))
+
+	result, err := client.SimulateC2B(C2BSimulateOptions{Amount: 100, MSISDN: "0708374149", BillRefNumber: "INV001"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.ResponseCode != "0" {
+		t.Errorf("unexpected response %+v", result)
+	}
+	expected := c2bSimulateRequest{ShortCode: "600986", CommandID: CustomerPayBillOnline, Amount: 100, Msisdn: "254708374149", BillRefNumber: "INV001"}
+	if received != expected {
+		t.Errorf("expected request %+v, got %+v", expected, received)
+	}
+
+	production := &Config{Environment: "production", BusinessShortcode: "123456", SecurityCredential: "cred"}
+	client = NewClient(production, WithBaseURL(server.URL), WithTokenSource(StaticToken("token")))
+	_, err = client.SimulateC2B(C2BSimulateOptions{Amount: 100, MSISDN: "0708374149"})
+	if err == nil || !strings.Contains(err.Error(), "only available in the sandbox") {
+		t.Errorf("expected production to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package callbacks
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// C2BPayment is a customer payment as posted by M-Pesa to the C2B confirmation
+// and validation URLs; both receive the same payload.
+type C2BPayment struct {
+	// TransactionType is "Pay Bill" or "Buy Goods"
+	TransactionType string `json:"TransactionType"`
+
+	// TransID is the M-Pesa receipt number of the payment
+	TransID string `json:"TransID"`
+
+	// TransTime is when the payment was made (YYYYMMDDHHmmss)
+	TransTime string `json:"TransTime"`
+
+	// TransAmount is the amount paid
+	TransAmount string `json:"TransAmount"`
+
+	// BusinessShortCode is the shortcode that received the payment
+	BusinessShortCode string `json:"BusinessShortCode"`
+
+	// BillRefNumber is the account number entered by the customer (Paybill only)
+	BillRefNumber string `json:"BillRefNumber"`
+
+	// InvoiceNumber is the invoice number of the payment, if any
+	InvoiceNumber string `json:"InvoiceNumber"`
+
+	// OrgAccountBalance is the balance of the shortcode after the payment (confirmation only)
+	OrgAccountBalance string `json:"OrgAccountBalance"`
+
+	// ThirdPartyTransID is the transaction ID the business returned on validation, if any
+	ThirdPartyTransID string `json:"ThirdPartyTransID"`
+
+	// MSISDN is the phone number of the paying customer, usually masked
+	MSISDN string `json:"MSISDN"`
+
+	// FirstName, MiddleName and LastName are the customer's names
+	FirstName  string `json:"FirstName"`
+	MiddleName string `json:"MiddleName"`
+	LastName   string `json:"LastName"`
+
+	// Extra holds any members of the payment not covered by the fields above
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// ParseC2BPayment decodes the JSON body posted by M-Pesa to a C2B confirmation or validation URL.
+// Amounts and balances sent as numbers are kept as text.
+func ParseC2BPayment(body []byte) (*C2BPayment, error) {
+	var payment C2BPayment
+	if err := json.Unmarshal(body, &payment); err != nil {
+		return nil, fmt.Errorf("failed to parse C2B payment: %w", err)
+	}
+	return &payment, nil
+}
+
+// UnmarshalJSON decodes a payment, keeping unknown members in Extra.
+func (p *C2BPayment) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	// Some integrations send amounts as numbers; read every member as text
+	text := make(map[string]string, len(members))
+	for name, value := range members {
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			s = string(value)
+		}
+		text[name] = s
+	}
+
+	type plain C2BPayment
+	encoded, err := json.Marshal(text)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(encoded, (*plain)(p)); err != nil {
+		return err
+	}
+
+	extra, err := unknownFields(data, reflect.TypeOf(*p))
+	if err != nil {
+		return err
+	}
+	p.Extra = extra
+	return nil
+}
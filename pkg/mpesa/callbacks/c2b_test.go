@@ -0,0 +1,30 @@
+package callbacks
+
+import (
+	"testing"
+)
+
+// TestParseC2BPayment tests decoding C2B confirmation payloads
+func TestParseC2BPayment(t *testing.T) {
+	body := []byte(`{"TransactionType":"Pay Bill","TransID":"RKTQDM7W6S","TransTime":"20191122063845","TransAmount":10,
+		"BusinessShortCode":"600638","BillRefNumber":"invoice008","InvoiceNumber":"","OrgAccountBalance":"49197.00",
+		"ThirdPartyTransID":"","MSISDN":"25470****149","FirstName":"John","MiddleName":"","LastName":"Doe","NewField":"kept"}`)
+
+	payment, err := ParseC2BPayment(body)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if payment.TransID != "RKTQDM7W6S" || payment.TransAmount != "10" || payment.BillRefNumber != "invoice008" {
+		t.Errorf("unexpected payment %+v", payment)
+	}
+	if payment.FirstName != "John" || payment.LastName != "Doe" || payment.OrgAccountBalance != "49197.00" {
+		t.Errorf("unexpected payment %+v", payment)
+	}
+	if string(payment.Extra["NewField"]) != `"kept"` || len(payment.Extra) != 1 {
+		t.Errorf("expected unknown field to be preserved, got %v", payment.Extra)
+	}
+
+	if _, err := ParseC2BPayment([]byte(`[]`)); err == nil {
+		t.Error("expected error for non-object payload")
+	}
+}
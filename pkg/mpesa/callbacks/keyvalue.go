@@ -0,0 +1,206 @@
+// Package callbacks decodes the payloads M-Pesa posts to result, callback,
+// confirmation and validation URLs.
+package callbacks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// numberType is the type of json.Number fields, which are decoded from the raw value
+var numberType = reflect.TypeOf(json.Number(""))
+
+// KeyValue is a single item of a key-value list such as ResultParameters or
+// CallbackMetadata. Values are kept as text, whether M-Pesa sent them as
+// strings or numbers.
+type KeyValue struct {
+	// Key is the name of the item ("Name" in STK callbacks)
+	Key string `json:"Key"`
+
+	// Value is the value of the item as text, empty if it was missing
+	Value string `json:"Value"`
+
+	// raw is the value exactly as sent
+	raw json.RawMessage
+}
+
+// UnmarshalJSON accepts string, number and missing values, and items named with Name instead of Key.
+func (kv *KeyValue) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Key   string          `json:"Key"`
+		Name  string          `json:"Name"`
+		Value json.RawMessage `json:"Value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	kv.Key = raw.Key
+	if kv.Key == "" {
+		kv.Key = raw.Name
+	}
+	kv.Value = ""
+	kv.raw = nil
+	if len(raw.Value) == 0 || bytes.Equal(raw.Value, []byte("null")) {
+		return nil
+	}
+
+	kv.raw = raw.Value
+	if raw.Value[0] == '"' {
+		return json.Unmarshal(raw.Value, &kv.Value)
+	}
+	kv.Value = string(raw.Value)
+	return nil
+}
+
+// KeyValues is a list of key-value items. M-Pesa sends a single item as an
+// object rather than a one-element array, so both forms are accepted.
+type KeyValues []KeyValue
+
+// UnmarshalJSON accepts an array of items or a single item.
+func (kvs *KeyValues) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var kv KeyValue
+		if err := json.Unmarshal(data, &kv); err != nil {
+			return err
+		}
+		*kvs = KeyValues{kv}
+		return nil
+	}
+
+	var items []KeyValue
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	*kvs = items
+	return nil
+}
+
+// Get returns the value of the item named key.
+func (kvs KeyValues) Get(key string) (string, bool) {
+	for _, kv := range kvs {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return "", false
+}
+
+// Map returns the items as a map from key to value.
+func (kvs KeyValues) Map() map[string]string {
+	m := make(map[string]string, len(kvs))
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+// Decode stores the items in the struct pointed to by v, matching keys to the
+// JSON names of its fields like encoding/json does. String fields receive the
+// value as text and json.Number fields the number; missing values are skipped.
+// Items without a matching field, and non-numeric values of json.Number fields
+// (e.g. "100.00 KES"), are returned, so that nothing is lost.
+func (kvs KeyValues) Decode(v interface{}) (KeyValues, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("decode target must be a pointer to a struct, got %T", v)
+	}
+	fields := jsonFields(rv.Elem().Type())
+
+	values := map[string]json.RawMessage{}
+	var unknown KeyValues
+	for _, kv := range kvs {
+		fieldType, ok := fields[strings.ToLower(kv.Key)]
+		if !ok {
+			unknown = append(unknown, kv)
+			continue
+		}
+		if kv.raw == nil && kv.Value == "" {
+			continue
+		}
+
+		switch {
+		case fieldType == numberType:
+			if kv.Value == "" {
+				continue
+			}
+			if !isNumber(kv.Value) {
+				unknown = append(unknown, kv)
+				continue
+			}
+			values[kv.Key] = json.RawMessage(kv.Value)
+		case fieldType.Kind() == reflect.String || kv.raw == nil:
+			quoted, err := json.Marshal(kv.Value)
+			if err != nil {
+				return nil, err
+			}
+			values[kv.Key] = quoted
+		default:
+			values[kv.Key] = kv.raw
+		}
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("failed to decode parameters: %w", err)
+	}
+
+	return unknown, nil
+}
+
+// jsonFields returns the types of the JSON-encoded fields of struct type t,
+// keyed by their lowercased JSON name.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[strings.ToLower(name)] = field.Type
+	}
+	return fields
+}
+
+// unknownFields returns the members of the JSON object data that have no
+// matching field in struct type t, or nil if there are none.
+func unknownFields(data []byte, t reflect.Type) (map[string]json.RawMessage, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+
+	fields := jsonFields(t)
+	var unknown map[string]json.RawMessage
+	for name, value := range members {
+		if _, ok := fields[strings.ToLower(name)]; ok {
+			continue
+		}
+		if unknown == nil {
+			unknown = map[string]json.RawMessage{}
+		}
+		unknown[name] = value
+	}
+	return unknown, nil
+}
+
+// isNumber reports whether value is a JSON number.
+func isNumber(value string) bool {
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil && json.Valid([]byte(value))
+}
@@ -0,0 +1,313 @@
+package callbacks
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Code is a result code. Daraja sends most codes as numbers, e.g. 0 or 2001, but
+// some as text, e.g. "SFC_IC0003", so both are accepted.
+type Code string
+
+// UnmarshalJSON accepts a JSON number or string.
+func (c *Code) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*c = Code(text)
+		return nil
+	}
+
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("result code must be a number or a string, got %s", data)
+	}
+	*c = Code(number)
+	return nil
+}
+
+// MarshalJSON writes numeric codes as numbers and other codes as strings.
+func (c Code) MarshalJSON() ([]byte, error) {
+	if isNumber(string(c)) {
+		return []byte(c), nil
+	}
+	return json.Marshal(string(c))
+}
+
+// String returns the code as text.
+func (c Code) String() string {
+	return string(c)
+}
+
+// Result is the outcome of an asynchronous request (B2C, B2B, account balance,
+// transaction status, reversal, ...) as posted by M-Pesa to the ResultURL.
+type Result struct {
+	// ResultType is 0 for a completed request
+	ResultType int `json:"ResultType"`
+
+	// ResultCode is 0 for success; any other code describes the failure
+	ResultCode Code `json:"ResultCode"`
+
+	// ResultDesc is a human-readable description of the result
+	ResultDesc string `json:"ResultDesc"`
+
+	// OriginatorConversationID is the unique identifier of the request from the originator
+	OriginatorConversationID string `json:"OriginatorConversationID"`
+
+	// ConversationID is the unique identifier M-Pesa assigned to the request
+	ConversationID string `json:"ConversationID"`
+
+	// TransactionID is the M-Pesa receipt number of the transaction, if any
+	TransactionID string `json:"TransactionID"`
+
+	// ResultParameters holds the API-specific details of the result
+	ResultParameters struct {
+		ResultParameter KeyValues `json:"ResultParameter"`
+	} `json:"ResultParameters"`
+
+	// ReferenceData echoes back reference items of the request
+	ReferenceData struct {
+		ReferenceItem KeyValues `json:"ReferenceItem"`
+	} `json:"ReferenceData"`
+
+	// Extra holds any members of the result not covered by the fields above
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// resultEnvelope is the JSON document posted to the ResultURL
+type resultEnvelope struct {
+	Result Result `json:"Result"`
+}
+
+// ParseResult decodes the JSON body posted by M-Pesa to a ResultURL.
+func ParseResult(body []byte) (*Result, error) {
+	var envelope resultEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse result: %w", err)
+	}
+	return &envelope.Result, nil
+}
+
+// UnmarshalJSON decodes a result, keeping unknown members in Extra.
+func (r *Result) UnmarshalJSON(data []byte) error {
+	type plain Result
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+
+	extra, err := unknownFields(data, reflect.TypeOf(*r))
+	if err != nil {
+		return err
+	}
+	r.Extra = extra
+	return nil
+}
+
+// Succeeded reports whether the request completed successfully.
+func (r *Result) Succeeded() bool {
+	return r.ResultCode.String() == "0"
+}
+
+// Parameter returns the value of the result parameter named key.
+func (r *Result) Parameter(key string) (string, bool) {
+	return r.ResultParameters.ResultParameter.Get(key)
+}
+
+// TransactionStatus decodes the parameters of a Transaction Status result.
+func (r *Result) TransactionStatus() (*TransactionStatusParameters, error) {
+	var params TransactionStatusParameters
+	extra, err := r.ResultParameters.ResultParameter.Decode(&params)
+	if err != nil {
+		return nil, err
+	}
+	params.Extra = extra
+	return &params, nil
+}
+
+// B2C decodes the parameters of a B2C payment result.
+func (r *Result) B2C() (*B2CParameters, error) {
+	var params B2CParameters
+	extra, err := r.ResultParameters.ResultParameter.Decode(&params)
+	if err != nil {
+		return nil, err
+	}
+	params.Extra = extra
+	return &params, nil
+}
+
+// B2B decodes the parameters of a B2B payment result.
+func (r *Result) B2B() (*B2BParameters, error) {
+	var params B2BParameters
+	extra, err := r.ResultParameters.ResultParameter.Decode(&params)
+	if err != nil {
+		return nil, err
+	}
+	params.Extra = extra
+	return &params, nil
+}
+
+// AccountBalance decodes the parameters of an Account Balance result.
+func (r *Result) AccountBalance() (*AccountBalanceParameters, error) {
+	var params AccountBalanceParameters
+	extra, err := r.ResultParameters.ResultParameter.Decode(&params)
+	if err != nil {
+		return nil, err
+	}
+	params.Extra = extra
+	return &params, nil
+}
+
+// Reversal decodes the parameters of a Reversal result.
+func (r *Result) Reversal() (*ReversalParameters, error) {
+	var params ReversalParameters
+	extra, err := r.ResultParameters.ResultParameter.Decode(&params)
+	if err != nil {
+		return nil, err
+	}
+	params.Extra = extra
+	return &params, nil
+}
+
+// TransactionStatusParameters are the result parameters of a Transaction Status query.
+type TransactionStatusParameters struct {
+	// ReceiptNo is the M-Pesa receipt number of the transaction
+	ReceiptNo string `json:"ReceiptNo"`
+
+	// TransactionStatus is the state of the transaction, e.g. "Completed"
+	TransactionStatus string `json:"TransactionStatus"`
+
+	// Amount is the amount of the transaction
+	Amount json.Number `json:"Amount"`
+
+	// DebitPartyName identifies the party the money came from
+	DebitPartyName string `json:"DebitPartyName"`
+
+	// CreditPartyName identifies the party the money went to
+	CreditPartyName string `json:"CreditPartyName"`
+
+	// DebitAccountType is the account the money was taken from
+	DebitAccountType string `json:"DebitAccountType"`
+
+	// DebitPartyCharges describes the charges paid by the debit party
+	DebitPartyCharges string `json:"DebitPartyCharges"`
+
+	// ReasonType is the type of the transaction, e.g. "Pay Bill Online"
+	ReasonType string `json:"ReasonType"`
+
+	// TransactionReason is the reason given for the transaction
+	TransactionReason string `json:"TransactionReason"`
+
+	// InitiatedTime is when the transaction was started (YYYYMMDDHHmmss)
+	InitiatedTime string `json:"InitiatedTime"`
+
+	// FinalisedTime is when the transaction was completed (YYYYMMDDHHmmss)
+	FinalisedTime string `json:"FinalisedTime"`
+
+	// ConversationID is the ConversationID of the original transaction
+	ConversationID string `json:"ConversationID"`
+
+	// OriginatorConversationID is the OriginatorConversationID of the original transaction
+	OriginatorConversationID string `json:"OriginatorConversationID"`
+
+	// Extra holds any parameters not covered by the fields above
+	Extra KeyValues `json:"-"`
+}
+
+// B2CParameters are the result parameters of a B2C payment.
+type B2CParameters struct {
+	// TransactionAmount is the amount paid to the customer
+	TransactionAmount json.Number `json:"TransactionAmount"`
+
+	// TransactionReceipt is the M-Pesa receipt number of the payment
+	TransactionReceipt string `json:"TransactionReceipt"`
+
+	// ReceiverPartyPublicName is the phone number and name of the customer
+	ReceiverPartyPublicName string `json:"ReceiverPartyPublicName"`
+
+	// TransactionCompletedDateTime is when the payment was completed, e.g. "19.12.2019 11:45:50"
+	TransactionCompletedDateTime string `json:"TransactionCompletedDateTime"`
+
+	// B2CRecipientIsRegisteredCustomer is "Y" if the customer is registered on M-Pesa
+	B2CRecipientIsRegisteredCustomer string `json:"B2CRecipientIsRegisteredCustomer"`
+
+	// B2CUtilityAccountAvailableFunds is the balance of the utility account after the payment
+	B2CUtilityAccountAvailableFunds json.Number `json:"B2CUtilityAccountAvailableFunds"`
+
+	// B2CWorkingAccountAvailableFunds is the balance of the working account after the payment
+	B2CWorkingAccountAvailableFunds json.Number `json:"B2CWorkingAccountAvailableFunds"`
+
+	// B2CChargesPaidAccountAvailableFunds is the balance of the charges paid account after the payment
+	B2CChargesPaidAccountAvailableFunds json.Number `json:"B2CChargesPaidAccountAvailableFunds"`
+
+	// Extra holds any parameters not covered by the fields above
+	Extra KeyValues `json:"-"`
+}
+
+// B2BParameters are the result parameters of a B2B payment.
+type B2BParameters struct {
+	// Amount is the amount paid
+	Amount json.Number `json:"Amount"`
+
+	// Currency is the currency of the payment, e.g. "KES"
+	Currency string `json:"Currency"`
+
+	// ReceiverPartyPublicName is the shortcode and name of the receiving business
+	ReceiverPartyPublicName string `json:"ReceiverPartyPublicName"`
+
+	// TransCompletedTime is when the payment was completed (YYYYMMDDHHmmss)
+	TransCompletedTime string `json:"TransCompletedTime"`
+
+	// DebitAccountBalance describes the balance of the paying account after the payment
+	DebitAccountBalance string `json:"DebitAccountBalance"`
+
+	// DebitPartyAffectedAccountBalance describes the affected accounts of the paying business
+	DebitPartyAffectedAccountBalance string `json:"DebitPartyAffectedAccountBalance"`
+
+	// DebitPartyCharges describes the charges paid by the paying business
+	DebitPartyCharges string `json:"DebitPartyCharges"`
+
+	// InitiatorAccountCurrentBalance describes the balance of the initiator's account
+	InitiatorAccountCurrentBalance string `json:"InitiatorAccountCurrentBalance"`
+
+	// Extra holds any parameters not covered by the fields above
+	Extra KeyValues `json:"-"`
+}
+
+// AccountBalanceParameters are the result parameters of an Account Balance query.
+type AccountBalanceParameters struct {
+	// AccountBalance lists the accounts as Name|Currency|Current|Available|Reserved|Uncleared, separated by &
+	AccountBalance string `json:"AccountBalance"`
+
+	// BOCompletedTime is when the query was completed (YYYYMMDDHHmmss)
+	BOCompletedTime string `json:"BOCompletedTime"`
+
+	// Extra holds any parameters not covered by the fields above
+	Extra KeyValues `json:"-"`
+}
+
+// ReversalParameters are the result parameters of a Reversal.
+type ReversalParameters struct {
+	// OriginalTransactionID is the receipt number of the reversed transaction
+	OriginalTransactionID string `json:"OriginalTransactionID"`
+
+	// Amount is the amount reversed
+	Amount json.Number `json:"Amount"`
+
+	// Charge is the charge of the reversal
+	Charge json.Number `json:"Charge"`
+
+	// CreditPartyPublicName is the party the money was returned to
+	CreditPartyPublicName string `json:"CreditPartyPublicName"`
+
+	// DebitPartyPublicName is the party the money was taken from
+	DebitPartyPublicName string `json:"DebitPartyPublicName"`
+
+	// DebitAccountBalance describes the balance of the debited account after the reversal
+	DebitAccountBalance string `json:"DebitAccountBalance"`
+
+	// TransCompletedTime is when the reversal was completed (YYYYMMDDHHmmss)
+	TransCompletedTime string `json:"TransCompletedTime"`
+
+	// Extra holds any parameters not covered by the fields above
+	Extra KeyValues `json:"-"`
+}
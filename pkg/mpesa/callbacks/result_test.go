@@ -0,0 +1,127 @@
+package callbacks
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestParseResultTransactionStatus tests decoding a Transaction Status result into typed parameters
+func TestParseResultTransactionStatus(t *testing.T) {
+	body := []byte(`{"Result":{"ResultType":0,"ResultCode":0,"ResultDesc":"The service request is processed successfully.",
+		"OriginatorConversationID":"1236-7134259-1","ConversationID":"AG_20210709_1234409f86436c583e3f","TransactionID":"SEI0000000",
+		"ResultParameters":{"ResultParameter":[{"Key":"DebitPartyName","Value":"600310 - Safaricom333"},{"Key":"CreditPartyName","Value":"254708374149 - John Doe"},
+		{"Key":"ReceiptNo","Value":"OEI2AK4Q16"},{"Key":"Amount","Value":100},{"Key":"TransactionStatus","Value":"Completed"},
+		{"Key":"FinalisedTime","Value":20210709130425},{"Key":"NewParameter","Value":"kept"}]},
+		"ReferenceData":{"ReferenceItem":{"Key":"Occasion"}},"NewMember":{"x":1}}}`)
+
+	result, err := ParseResult(body)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !result.Succeeded() || result.TransactionID != "SEI0000000" {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if string(result.Extra["NewMember"]) != `{"x":1}` || len(result.Extra) != 1 {
+		t.Errorf("expected unknown member to be preserved, got %v", result.Extra)
+	}
+
+	status, err := result.TransactionStatus()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if status.ReceiptNo != "OEI2AK4Q16" || status.Amount.String() != "100" || status.FinalisedTime != "20210709130425" {
+		t.Errorf("unexpected parameters %+v", status)
+	}
+	if status.CreditPartyName != "254708374149 - John Doe" || status.TransactionStatus != "Completed" {
+		t.Errorf("unexpected parties %+v", status)
+	}
+	if value, ok := status.Extra.Get("NewParameter"); !ok || value != "kept" || len(status.Extra) != 1 {
+		t.Errorf("expected unknown parameter to be preserved, got %v", status.Extra)
+	}
+}
+
+// TestResultTypedParameters tests the typed parameters of B2C, B2B, balance and reversal results
+func TestResultTypedParameters(t *testing.T) {
+	parse := func(params string) *Result {
+		t.Helper()
+		result, err := ParseResult([]byte(`{"Result":{"ResultCode":0,"ResultParameters":{"ResultParameter":` + params + `}}}`))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		return result
+	}
+
+	b2c, err := parse(`[{"Key":"TransactionAmount","Value":10},{"Key":"TransactionReceipt","Value":"NLJ41HAY6Q"},
+		{"Key":"B2CRecipientIsRegisteredCustomer","Value":"Y"},{"Key":"B2CWorkingAccountAvailableFunds","Value":"900000.00"}]`).B2C()
+	if err != nil || b2c.TransactionAmount.String() != "10" || b2c.TransactionReceipt != "NLJ41HAY6Q" || b2c.B2CWorkingAccountAvailableFunds.String() != "900000.00" {
+		t.Errorf("unexpected B2C parameters %+v (%v)", b2c, err)
+	}
+
+	b2b, err := parse(`{"Key":"Amount","Value":"190.00"}`).B2B()
+	if err != nil || b2b.Amount.String() != "190.00" {
+		t.Errorf("unexpected B2B parameters %+v (%v)", b2b, err)
+	}
+
+	balance, err := parse(`[{"Key":"AccountBalance","Value":"Working Account|KES|700000.00|700000.00|0.00|0.00"},{"Key":"BOCompletedTime","Value":20200109125710}]`).AccountBalance()
+	if err != nil || balance.BOCompletedTime != "20200109125710" || balance.AccountBalance == "" {
+		t.Errorf("unexpected balance parameters %+v (%v)", balance, err)
+	}
+
+	reversal, err := parse(`[{"Key":"OriginalTransactionID","Value":"OEI2AK4Q16"},{"Key":"Amount","Value":1},{"Key":"Charge","Value":""}]`).Reversal()
+	if err != nil || reversal.OriginalTransactionID != "OEI2AK4Q16" || reversal.Amount.String() != "1" || reversal.Charge != "" {
+		t.Errorf("unexpected reversal parameters %+v (%v)", reversal, err)
+	}
+
+	// A non-numeric amount is kept in Extra instead of failing the decode
+	status, err := parse(`[{"Key":"Amount","Value":"100.00 KES"},{"Key":"DebitPartyName","Value":"600310 - Safaricom333"}]`).TransactionStatus()
+	if err != nil || status.Amount != "" || status.DebitPartyName != "600310 - Safaricom333" {
+		t.Errorf("unexpected transaction status parameters %+v (%v)", status, err)
+	}
+	if value, ok := status.Extra.Get("Amount"); !ok || value != "100.00 KES" {
+		t.Errorf("expected non-numeric amount in Extra, got %v", status.Extra)
+	}
+}
+
+// TestKeyValuesMap tests converting key-value items to a map
+func TestKeyValuesMap(t *testing.T) {
+	kvs := KeyValues{{Key: "Amount", Value: "10"}, {Key: "ReceiptNo", Value: "OEI2AK4Q16"}}
+
+	m := kvs.Map()
+	if len(m) != 2 || m["Amount"] != "10" || m["ReceiptNo"] != "OEI2AK4Q16" {
+		t.Errorf("unexpected map %v", m)
+	}
+
+	var params TransactionStatusParameters
+	extra, err := kvs.Decode(&params)
+	if err != nil || params.Amount.String() != "10" || params.ReceiptNo != "OEI2AK4Q16" || len(extra) != 0 {
+		t.Errorf("expected items built in code to decode, got %+v, %v (%v)", params, extra, err)
+	}
+
+	if _, err := kvs.Decode(params); err == nil {
+		t.Error("expected error for non-pointer target")
+	}
+}
+
+// TestParseResultAlphanumericCode tests results whose ResultCode is text rather than a number
+func TestParseResultAlphanumericCode(t *testing.T) {
+	body := []byte(`{"Result":{"ResultType":0,"ResultCode":"SFC_IC0003","ResultDesc":"Operator does not exist.",
+		"OriginatorConversationID":"10816-694520-2","ConversationID":"AG_20200927_00007d4c98884c889b25",
+		"TransactionID":"MIR0000000","ReferenceData":{"ReferenceItem":{"Key":"QueueTimeoutURL",
+		"Value":"https://internalsandbox.safaricom.co.ke/mpesa/b2cresults/v1/submit"}}}}`)
+
+	result, err := ParseResult(body)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Succeeded() || result.ResultCode.String() != "SFC_IC0003" {
+		t.Errorf("expected failed result with code SFC_IC0003, got %+v", result)
+	}
+
+	// Codes keep their JSON type when encoded again
+	for code, expected := range map[Code]string{"SFC_IC0003": `"SFC_IC0003"`, "2001": `2001`} {
+		data, err := json.Marshal(code)
+		if err != nil || string(data) != expected {
+			t.Errorf("expected %s, got %s %v", expected, data, err)
+		}
+	}
+}
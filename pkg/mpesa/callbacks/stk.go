@@ -0,0 +1,98 @@
+package callbacks
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// STKCallback is the outcome of an STK Push payment as posted by M-Pesa to the CallBackURL.
+type STKCallback struct {
+	// MerchantRequestID is the global unique identifier of the payment request
+	MerchantRequestID string `json:"MerchantRequestID"`
+
+	// CheckoutRequestID is the global unique identifier of the checkout transaction
+	CheckoutRequestID string `json:"CheckoutRequestID"`
+
+	// ResultCode is 0 for success, e.g. 1032 if the customer cancelled
+	ResultCode Code `json:"ResultCode"`
+
+	// ResultDesc is a human-readable description of the result
+	ResultDesc string `json:"ResultDesc"`
+
+	// CallbackMetadata holds the details of a successful payment
+	CallbackMetadata struct {
+		Item KeyValues `json:"Item"`
+	} `json:"CallbackMetadata"`
+
+	// Extra holds any members of the callback not covered by the fields above
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// stkCallbackEnvelope is the JSON document posted to the CallBackURL
+type stkCallbackEnvelope struct {
+	Body struct {
+		STKCallback STKCallback `json:"stkCallback"`
+	} `json:"Body"`
+}
+
+// STKMetadata is the CallbackMetadata of a successful STK Push payment.
+type STKMetadata struct {
+	// Amount is the amount paid
+	Amount json.Number `json:"Amount"`
+
+	// MpesaReceiptNumber is the M-Pesa receipt number of the payment
+	MpesaReceiptNumber string `json:"MpesaReceiptNumber"`
+
+	// Balance is the balance of the paying account, if M-Pesa sent it
+	Balance string `json:"Balance"`
+
+	// TransactionDate is when the payment was made (YYYYMMDDHHmmss)
+	TransactionDate string `json:"TransactionDate"`
+
+	// PhoneNumber is the phone number that paid
+	PhoneNumber string `json:"PhoneNumber"`
+
+	// Extra holds any items not covered by the fields above
+	Extra KeyValues `json:"-"`
+}
+
+// ParseSTKCallback decodes the JSON body posted by M-Pesa to an STK Push CallBackURL.
+func ParseSTKCallback(body []byte) (*STKCallback, error) {
+	var envelope stkCallbackEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse STK callback: %w", err)
+	}
+	return &envelope.Body.STKCallback, nil
+}
+
+// UnmarshalJSON decodes a callback, keeping unknown members in Extra.
+func (c *STKCallback) UnmarshalJSON(data []byte) error {
+	type plain STKCallback
+	if err := json.Unmarshal(data, (*plain)(c)); err != nil {
+		return err
+	}
+
+	extra, err := unknownFields(data, reflect.TypeOf(*c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	return nil
+}
+
+// Succeeded reports whether the customer completed the payment.
+func (c *STKCallback) Succeeded() bool {
+	return c.ResultCode.String() == "0"
+}
+
+// Metadata decodes the CallbackMetadata of the payment.
+func (c *STKCallback) Metadata() (*STKMetadata, error) {
+	var metadata STKMetadata
+	extra, err := c.CallbackMetadata.Item.Decode(&metadata)
+	if err != nil {
+		return nil, err
+	}
+	metadata.Extra = extra
+	return &metadata, nil
+}
@@ -0,0 +1,43 @@
+package callbacks
+
+import (
+	"testing"
+)
+
+// TestParseSTKCallback tests decoding successful and cancelled STK callbacks
+func TestParseSTKCallback(t *testing.T) {
+	body := []byte(`{"Body":{"stkCallback":{"MerchantRequestID":"29115-34620561-1","CheckoutRequestID":"ws_CO_191220191020363925",
+		"ResultCode":0,"ResultDesc":"The service request is processed successfully.",
+		"CallbackMetadata":{"Item":[{"Name":"Amount","Value":1.00},{"Name":"MpesaReceiptNumber","Value":"NLJ7RT61SV"},{"Name":"Balance"},
+		{"Name":"TransactionDate","Value":20191219102115},{"Name":"PhoneNumber","Value":254708374149},{"Name":"Extra","Value":"x"}]}}}}`)
+
+	callback, err := ParseSTKCallback(body)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !callback.Succeeded() || callback.CheckoutRequestID != "ws_CO_191220191020363925" {
+		t.Errorf("unexpected callback %+v", callback)
+	}
+
+	metadata, err := callback.Metadata()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if metadata.Amount.String() != "1.00" || metadata.MpesaReceiptNumber != "NLJ7RT61SV" || metadata.Balance != "" {
+		t.Errorf("unexpected metadata %+v", metadata)
+	}
+	if metadata.PhoneNumber != "254708374149" || metadata.TransactionDate != "20191219102115" {
+		t.Errorf("expected numeric items as text, got %+v", metadata)
+	}
+	if len(metadata.Extra) != 1 || metadata.Extra[0].Key != "Extra" {
+		t.Errorf("expected unknown item to be preserved, got %v", metadata.Extra)
+	}
+
+	cancelled, err := ParseSTKCallback([]byte(`{"Body":{"stkCallback":{"MerchantRequestID":"1","CheckoutRequestID":"2","ResultCode":1032,"ResultDesc":"Request cancelled by user"}}}`))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cancelled.Succeeded() || cancelled.ResultCode.String() != "1032" || cancelled.Extra != nil {
+		t.Errorf("unexpected cancelled callback %+v", cancelled)
+	}
+}
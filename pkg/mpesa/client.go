@@ -0,0 +1,360 @@
+package mpesa
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Base URLs of the Daraja API environments
+const (
+	SandboxBaseURL    = "https://sandbox.safaricom.co.ke"
+	ProductionBaseURL = "https://api.safaricom.co.ke"
+)
+
+const (
+	oauthPath             = "/oauth/v1/generate?grant_type=client_credentials"
+	transactionStatusPath = "/mpesa/transactionstatus/v1/query"
+
+	defaultTimeout   = 10 * time.Second
+	defaultUserAgent = "mpesa-cli"
+
+	// invalidTokenErrorCode is the Daraja error code for an invalid access token
+	invalidTokenErrorCode = "404.001.03"
+
+	// tokenRefreshMargin is how long before expiry an access token is refreshed
+	tokenRefreshMargin = time.Minute
+)
+
+// TokenSource supplies the access tokens used to authenticate API requests.
+type TokenSource interface {
+	Token() (string, error)
+}
+
+// StaticToken is a TokenSource that always returns the same access token.
+type StaticToken string
+
+// Token returns the static access token.
+func (t StaticToken) Token() (string, error) {
+	return string(t), nil
+}
+
+// tokenInvalidator is implemented by token sources that can discard a token
+// the API has rejected, so that the next call to Token fetches a new one.
+type tokenInvalidator interface {
+	Invalidate()
+}
+
+// credentialsTokenSource fetches access tokens from the OAuth endpoint of its
+// client using a consumer key and secret, reusing each token until shortly
+// before it expires. With a TokenCache, tokens are also shared between processes.
+type credentialsTokenSource struct {
+	client         *Client
+	consumerKey    string
+	consumerSecret string
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+func (s *credentialsTokenSource) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accessToken != "" && time.Now().Add(tokenRefreshMargin).Before(s.expiresAt) {
+		return s.accessToken, nil
+	}
+
+	cache := s.client.tokenCache
+	if cache != nil {
+		if accessToken, expiresAt, ok := cache.Get(s.cacheKey()); ok && time.Now().Add(tokenRefreshMargin).Before(expiresAt) {
+			s.accessToken, s.expiresAt = accessToken, expiresAt
+			return accessToken, nil
+		}
+	}
+
+	accessToken, expiresIn, err := s.client.fetchToken(s.client.baseURL+oauthPath, s.consumerKey, s.consumerSecret)
+	if err != nil {
+		return "", err
+	}
+
+	s.accessToken = accessToken
+	s.expiresAt = time.Now().Add(expiresIn)
+
+	// Caching is best effort: a token we cannot persist is still usable
+	if cache != nil && expiresIn > 0 {
+		_ = cache.Put(s.cacheKey(), accessToken, s.expiresAt)
+	}
+
+	return accessToken, nil
+}
+
+// Invalidate discards the current token, both in memory and in the token cache.
+func (s *credentialsTokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.accessToken = ""
+	s.expiresAt = time.Time{}
+	if s.client.tokenCache != nil {
+		_ = s.client.tokenCache.Delete(s.cacheKey())
+	}
+}
+
+// cacheKey keys tokens by environment, or by base URL when the client was pointed
+// elsewhere with WithBaseURL, so that tokens of a mock server or proxy are never
+// handed to the real environment and vice versa.
+func (s *credentialsTokenSource) cacheKey() string {
+	environment := s.client.config.Environment
+	if s.client.baseURL != BaseURLForEnvironment(environment) {
+		return TokenCacheKey(s.client.baseURL, s.consumerKey)
+	}
+	return TokenCacheKey(environment, s.consumerKey)
+}
+
+// APIError is returned when the M-Pesa API answers a request with a non-200 status.
+// The Daraja error fields are populated when the response body contains them.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int `json:"-"`
+
+	// Body is the raw response body
+	Body string `json:"-"`
+
+	// RequestID is the Daraja request identifier, if any
+	RequestID string `json:"requestId"`
+
+	// ErrorCode is the Daraja error code, e.g. "404.001.03"
+	ErrorCode string `json:"errorCode"`
+
+	// ErrorMessage is the Daraja error description
+	ErrorMessage string `json:"errorMessage"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// Client talks to a single M-Pesa environment. All API operations hang off a
+// Client, so several clients for different environments or profiles can be
+// used side by side in one process.
+type Client struct {
+	config     *Config
+	baseURL    string
+	httpClient *http.Client
+	timeout    time.Duration
+	userAgent  string
+	tokens     TokenSource
+	tokenCache *TokenCache
+	passkey    string
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithBaseURL overrides the API base URL, e.g. to point the client at a local test server.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sets the http.Client used to send requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout sets the timeout applied to every request. Without it, the timeout
+// of the http.Client is used, or 10 seconds if that has none.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithTokenSource sets the source of access tokens for authenticated requests.
+func WithTokenSource(tokens TokenSource) Option {
+	return func(c *Client) {
+		c.tokens = tokens
+	}
+}
+
+// WithCredentials authenticates requests with access tokens obtained from the
+// client's OAuth endpoint using the given consumer key and secret.
+func WithCredentials(consumerKey, consumerSecret string) Option {
+	return func(c *Client) {
+		c.tokens = &credentialsTokenSource{client: c, consumerKey: consumerKey, consumerSecret: consumerSecret}
+	}
+}
+
+// WithTokenCache persists access tokens obtained with WithCredentials in cache,
+// so that they are reused until shortly before they expire.
+func WithTokenCache(cache *TokenCache) Option {
+	return func(c *Client) {
+		c.tokenCache = cache
+	}
+}
+
+// NewClient creates a Client for the given configuration.
+// If config is nil, the default sandbox configuration is used. Unless
+// overridden with WithBaseURL, the base URL is derived from config.Environment.
+func NewClient(config *Config, opts ...Option) *Client {
+	if config == nil {
+		config = GetDefaultConfig()
+	}
+
+	c := &Client{
+		config:     config,
+		httpClient: &http.Client{},
+		userAgent:  defaultUserAgent,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.timeout == 0 && c.httpClient.Timeout == 0 {
+		c.timeout = defaultTimeout
+	}
+
+	if c.baseURL == "" {
+		c.baseURL = BaseURLForEnvironment(config.Environment)
+	}
+
+	return c
+}
+
+// BaseURLForEnvironment returns the Daraja base URL for "sandbox" or "production".
+// Any other value is treated as sandbox.
+func BaseURLForEnvironment(environment string) string {
+	if environment == "production" {
+		return ProductionBaseURL
+	}
+	return SandboxBaseURL
+}
+
+// Config returns the configuration the client was created with.
+func (c *Client) Config() *Config {
+	return c.config
+}
+
+// BaseURL returns the API base URL the client sends requests to.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Token returns an access token from the client's token source.
+func (c *Client) Token() (string, error) {
+	if c.tokens == nil {
+		return "", fmt.Errorf("no token source configured")
+	}
+	return c.tokens.Token()
+}
+
+// GetAccessToken authenticates against the client's OAuth endpoint using consumer credentials.
+func (c *Client) GetAccessToken(consumerKey, consumerSecret string) (string, error) {
+	accessToken, _, err := c.fetchToken(c.baseURL+oauthPath, consumerKey, consumerSecret)
+	return accessToken, err
+}
+
+// do sends req with the client's headers and timeout and returns the response body.
+// Non-200 responses are reported as *APIError.
+func (c *Client) do(req *http.Request) ([]byte, error) {
+	req.Header.Set("User-Agent", c.userAgent)
+
+	httpClient := c.httpClient
+	if c.timeout > 0 && httpClient.Timeout != c.timeout {
+		withTimeout := *httpClient
+		withTimeout.Timeout = c.timeout
+		httpClient = &withTimeout
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+		_ = json.Unmarshal(body, apiErr)
+		return nil, apiErr
+	}
+
+	return body, nil
+}
+
+// postJSON sends payload as an authenticated JSON POST to path and decodes the response into out.
+// If the API rejects the access token, the token is invalidated and the request retried once.
+func (c *Client) postJSON(path string, payload, out interface{}) error {
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error marshalling request body: %w", err)
+	}
+
+	respBody, err := c.postAuthorized(path, jsonBody)
+	if isInvalidTokenError(err) {
+		if invalidator, ok := c.tokens.(tokenInvalidator); ok {
+			invalidator.Invalidate()
+			respBody, err = c.postAuthorized(path, jsonBody)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+// postAuthorized sends jsonBody to path with a bearer token from the client's token source.
+func (c *Client) postAuthorized(path string, jsonBody []byte) ([]byte, error) {
+	accessToken, err := c.Token()
+	if err != nil {
+		return nil, fmt.Errorf("error getting access token: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", c.baseURL+path, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	// Set the required headers
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	return c.do(req)
+}
+
+// isInvalidTokenError reports whether err is the API rejecting an access token.
+// Daraja answers expired or unknown tokens with 401 or with error code 404.001.03.
+func isInvalidTokenError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusUnauthorized || apiErr.ErrorCode == invalidTokenErrorCode
+}
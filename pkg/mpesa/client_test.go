@@ -0,0 +1,168 @@
+package mpesa
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testConfig returns the default sandbox configuration with the security credential
+// the initiator APIs require
+func testConfig() *Config {
+	config := GetDefaultConfig()
+	config.SecurityCredential = "EncryptedCredential"
+	return config
+}
+
+// TestNewClientBaseURL tests that the base URL follows the configured environment
+func TestNewClientBaseURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *Config
+		opts     []Option
+		expected string
+	}{
+		{name: "nil config", config: nil, expected: SandboxBaseURL},
+		{name: "sandbox", config: &Config{Environment: "sandbox"}, expected: SandboxBaseURL},
+		{name: "production", config: &Config{Environment: "production"}, expected: ProductionBaseURL},
+		{
+			name:     "override",
+			config:   &Config{Environment: "production"},
+			opts:     []Option{WithBaseURL("http://127.0.0.1:8080/")},
+			expected: "http://127.0.0.1:8080",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(tt.config, tt.opts...)
+			if client.BaseURL() != tt.expected {
+				t.Errorf("expected base URL '%s', got '%s'", tt.expected, client.BaseURL())
+			}
+		})
+	}
+}
+
+// TestClientCredentialsTokenSource tests that requests authenticate against the client's own OAuth endpoint
+func TestClientCredentialsTokenSource(t *testing.T) {
+	const userAgent = "mpesa-cli/test"
+
+	tokenRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != userAgent {
+			t.Errorf("expected User-Agent '%s', got '%s'", userAgent, r.Header.Get("User-Agent"))
+		}
+
+		switch r.URL.Path {
+		case "/oauth/v1/generate":
+			tokenRequests++
+			user, pass, ok := r.BasicAuth()
+			if !ok || user != "key" || pass != "secret" {
+				http.Error(w, "invalid credentials", http.StatusUnauthorized)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "fresh-token", "expires_in": "3599"})
+		case "/mpesa/transactionstatus/v1/query":
+			if r.Header.Get("Authorization") != "Bearer fresh-token" {
+				http.Error(w, "invalid token", http.StatusUnauthorized)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(transactionStatusResponse{ResponseCode: "0"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(GetDefaultConfig(),
+		WithBaseURL(server.URL),
+		WithCredentials("key", "secret"),
+		WithUserAgent(userAgent),
+		WithTimeout(5*time.Second),
+	)
+
+	for i := 0; i < 2; i++ {
+		result, err := client.QueryTransaction("TEST12345")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if result.ResponseCode != "0" {
+			t.Errorf("expected response code '0', got '%s'", result.ResponseCode)
+		}
+	}
+
+	if tokenRequests != 1 {
+		t.Errorf("expected the access token to be reused, got %d token requests", tokenRequests)
+	}
+}
+
+// TestClientWithoutTokenSource tests that authenticated calls fail without a token source
+func TestClientWithoutTokenSource(t *testing.T) {
+	client := NewClient(GetDefaultConfig(), WithBaseURL("http://127.0.0.1:0"))
+
+	if _, err := client.QueryTransaction("TEST12345"); err == nil {
+		t.Error("expected error without a token source, got nil")
+	}
+}
+
+// TestClientTokenCache tests that cached tokens are shared between clients and invalidated when rejected
+func TestClientTokenCache(t *testing.T) {
+	tokenRequests := 0
+	validToken := "token-1"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/v1/generate":
+			tokenRequests++
+			validToken = fmt.Sprintf("token-%d", tokenRequests)
+			_ = json.NewEncoder(w).Encode(map[string]string{"access_token": validToken, "expires_in": "3599"})
+		default:
+			if r.Header.Get("Authorization") != "Bearer "+validToken {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"errorCode":"404.001.03","errorMessage":"Invalid Access Token"}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(transactionStatusResponse{ResponseCode: "0"})
+		}
+	}))
+	defer server.Close()
+
+	cache := NewTokenCache(filepath.Join(t.TempDir(), "tokens.json"))
+	newTestClient := func() *Client {
+		return NewClient(GetDefaultConfig(), WithBaseURL(server.URL), WithCredentials("key", "secret"), WithTokenCache(cache))
+	}
+
+	// Two separate clients, as in two CLI invocations, share one token
+	for i := 0; i < 2; i++ {
+		if _, err := newTestClient().QueryTransaction("TEST12345"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if tokenRequests != 1 {
+		t.Fatalf("expected 1 token request, got %d", tokenRequests)
+	}
+
+	// A token revoked server-side is dropped from the cache and replaced
+	validToken = "revoked"
+	if _, err := newTestClient().QueryTransaction("TEST12345"); err != nil {
+		t.Fatalf("expected retry with fresh token to succeed, got %v", err)
+	}
+	if tokenRequests != 2 {
+		t.Errorf("expected a new token after invalidation, got %d token requests", tokenRequests)
+	}
+
+	cached, _, ok := cache.Get(TokenCacheKey(server.URL, "key"))
+	if !ok || cached != "token-2" {
+		t.Errorf("expected refreshed token in cache, got '%s'", cached)
+	}
+
+	// Tokens of a custom base URL must not be used against the real environment
+	if cached, _, ok := cache.Get(TokenCacheKey("sandbox", "key")); ok {
+		t.Errorf("expected no sandbox token in cache, got '%s'", cached)
+	}
+}
@@ -1,8 +1,12 @@
 package mpesa
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"reflect"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -26,83 +30,224 @@ type Config struct {
 
 	// QueueTimeOutURL is the path that stores information of time out transaction
 	QueueTimeOutURL string `mapstructure:"queue_timeout_url"`
+
+	// CallbackURL receives the results of Lipa Na M-Pesa Online (STK Push) requests
+	CallbackURL string `mapstructure:"callback_url"`
+
+	// CertificatePath is the public certificate used to generate the SecurityCredential,
+	// replacing the certificate bundled for the environment
+	CertificatePath string `mapstructure:"certificate_path"`
+
+	// Profile is the name of the profile this configuration was loaded for
+	Profile string `mapstructure:"-"`
 }
 
-// GetConfig returns the current configuration, loading from file and environment variables
+// envPrefix is the prefix of environment variables that override config values
+const envPrefix = "MPESA"
+
+// GetConfig returns the configuration of the active profile, loading from file and environment variables.
+// The active profile is selected as described for ResolveProfile.
 func GetConfig() (*Config, error) {
+	return GetProfileConfig("")
+}
+
+// GetProfileConfig returns the configuration of the named profile, or of the active
+// profile if name is empty. Values from the profile's block under "profiles" in the
+// config file take precedence over top-level values, and environment variables take
+// precedence over both.
+func GetProfileConfig(name string) (*Config, error) {
+	config, err := LoadProfileConfig(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// LoadProfileConfig is GetProfileConfig without the requirement of a security_credential
+// in production, for profiles whose credential is generated from a stored initiator
+// password when a request is made (see ResolveSecurityCredential).
+func LoadProfileConfig(name string) (*Config, error) {
+	config, err := mergeProfileConfig(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate required fields
+	if err := validateSettings(config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// ProfileEnvironment returns the environment of the named profile, or of the active
+// profile if name is empty, checking only that it is sandbox or production.
+func ProfileEnvironment(name string) (string, error) {
+	config, err := mergeProfileConfig(name)
+	if err != nil {
+		return "", err
+	}
+
+	if err := validateEnvironment(config); err != nil {
+		return "", err
+	}
+
+	return config.Environment, nil
+}
+
+// mergeProfileConfig merges the named profile's settings over the top-level ones without validating them.
+func mergeProfileConfig(name string) (*Config, error) {
+	if err := readConfig(); err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		name = ResolveProfile("")
+	}
+
+	// Merge top-level settings with the profile block without touching the global viper state
+	settings := viper.New()
+	for _, key := range configKeys() {
+		if value := viper.Get(key); value != nil {
+			settings.Set(key, value)
+		}
+	}
+
+	if block := viper.Sub(profilesKey + "." + name); block != nil {
+		for _, key := range configKeys() {
+			if _, fromEnv := os.LookupEnv(envPrefix + "_" + strings.ToUpper(key)); block.IsSet(key) && !fromEnv {
+				settings.Set(key, block.Get(key))
+			}
+		}
+	} else if name != DefaultProfile {
+		return nil, fmt.Errorf("profile '%s' not found in config file", name)
+	}
+
+	var config Config
+	if err := settings.Unmarshal(&config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	config.Profile = name
+
+	return &config, nil
+}
+
+// readConfig sets defaults, config file locations and environment variable bindings,
+// then reads the config file if one exists.
+func readConfig() error {
 	// Set defaults
 	viper.SetDefault("environment", "sandbox")
 	viper.SetDefault("initiator", "testapi")
 	viper.SetDefault("result_url", "https://domain.com/result")
 	viper.SetDefault("queue_timeout_url", "https://domain.com/timeout")
+	viper.SetDefault("callback_url", "https://domain.com/callback")
 
-	// Try to read config file
-	viper.SetConfigName("mpesa-cli")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(".")
-	viper.AddConfigPath("$HOME/.config/mpesa-cli")
-	viper.AddConfigPath("/etc/mpesa-cli")
+	// Try to read config file, unless one was set explicitly (e.g. with --config);
+	// SetConfigName would discard it
+	if viper.ConfigFileUsed() == "" {
+		viper.SetConfigName("mpesa-cli")
+		viper.SetConfigType("yaml")
+		viper.AddConfigPath(".")
+		viper.AddConfigPath("$HOME/.config/mpesa-cli")
+		viper.AddConfigPath("/etc/mpesa-cli")
+	}
 
 	// Read environment variables
 	viper.AutomaticEnv()
-	viper.SetEnvPrefix("MPESA")
+	viper.SetEnvPrefix(envPrefix)
 
 	// Bind environment variables explicitly for underscore handling
-	_ = viper.BindEnv("environment")
-	_ = viper.BindEnv("business_shortcode")
-	_ = viper.BindEnv("security_credential")
-	_ = viper.BindEnv("initiator")
-	_ = viper.BindEnv("result_url")
-	_ = viper.BindEnv("queue_timeout_url")
+	for _, key := range configKeys() {
+		_ = viper.BindEnv(key)
+	}
 
 	// Read config file if it exists (don't error if it doesn't)
 	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return nil, fmt.Errorf("failed to read config file: %w", err)
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to read config file: %w", err)
 		}
 	}
 
-	var config Config
-	if err := viper.Unmarshal(&config); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
-	}
+	return nil
+}
 
-	// Validate required fields
-	if err := validateConfig(&config); err != nil {
-		return nil, err
+// configKeys returns the config file keys of all Config fields.
+func configKeys() []string {
+	t := reflect.TypeOf(Config{})
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if key := t.Field(i).Tag.Get("mapstructure"); key != "" && key != "-" {
+			keys = append(keys, key)
+		}
 	}
+	return keys
+}
 
-	return &config, nil
+// configValues returns the non-empty fields of config keyed by their config file key.
+func configValues(config *Config) map[string]interface{} {
+	values := map[string]interface{}{}
+	v := reflect.ValueOf(config).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		key := v.Type().Field(i).Tag.Get("mapstructure")
+		if key == "" || key == "-" || v.Field(i).IsZero() {
+			continue
+		}
+		values[key] = v.Field(i).Interface()
+	}
+	return values
 }
 
 // validateConfig ensures required configuration values are set
 func validateConfig(config *Config) error {
+	if err := validateSettings(config); err != nil {
+		return err
+	}
+
+	if config.Environment == "production" && config.SecurityCredential == "" {
+		return fmt.Errorf("security_credential is required for production environment (or store the initiator password with 'mpesa-cli credential store')")
+	}
+
+	return nil
+}
+
+// validateEnvironment ensures the environment is sandbox or production.
+func validateEnvironment(config *Config) error {
 	if config.Environment != "sandbox" && config.Environment != "production" {
 		return fmt.Errorf("environment must be either 'sandbox' or 'production', got: %s", config.Environment)
 	}
 
-	// For production, we need these values
-	if config.Environment == "production" {
-		if config.BusinessShortcode == "" {
-			return fmt.Errorf("business_shortcode is required for production environment")
-		}
-		if config.SecurityCredential == "" {
-			return fmt.Errorf("security_credential is required for production environment")
-		}
+	return nil
+}
+
+// validateSettings ensures the environment and, for production, the business shortcode are set.
+func validateSettings(config *Config) error {
+	if err := validateEnvironment(config); err != nil {
+		return err
+	}
+
+	if config.Environment == "production" && config.BusinessShortcode == "" {
+		return fmt.Errorf("business_shortcode is required for production environment")
 	}
 
 	return nil
 }
 
-// GetDefaultConfig returns a default configuration for sandbox testing
+// GetDefaultConfig returns a default configuration for sandbox testing. It has no
+// security credential; see ResolveSecurityCredential to generate one.
 func GetDefaultConfig() *Config {
 	return &Config{
-		BusinessShortcode:  "600986",                 // Default sandbox shortcode
-		SecurityCredential: "YourSecurityCredential", // Placeholder for sandbox
-		Environment:        "sandbox",
-		Initiator:          "testapi",
-		ResultURL:          "https://domain.com/result",
-		QueueTimeOutURL:    "https://domain.com/timeout",
+		BusinessShortcode: "600986", // Default sandbox shortcode
+		Environment:       "sandbox",
+		Initiator:         "testapi",
+		ResultURL:         "https://domain.com/result",
+		QueueTimeOutURL:   "https://domain.com/timeout",
+		CallbackURL:       "https://domain.com/callback",
+		Profile:           DefaultProfile,
 	}
 }
 
@@ -119,6 +264,10 @@ environment: sandbox
 
 # Your security credential (required for production)  
 # security_credential: "your-encrypted-credential"
+# Alternatively, store the initiator password with 'mpesa-cli credential store'
+# and the security credential is generated when needed. The certificate used to
+# encrypt it can be overridden with:
+# certificate_path: "/path/to/ProductionCertificate.cer"
 
 # API initiator name (optional, defaults to "testapi")
 # initiator: "your-initiator-name"
@@ -126,6 +275,29 @@ environment: sandbox
 # Callback URLs for transaction results (optional)
 # result_url: "https://yourdomain.com/mpesa/result"
 # queue_timeout_url: "https://yourdomain.com/mpesa/timeout"
+
+# Callback URL for STK Push results (optional)
+# callback_url: "https://yourdomain.com/mpesa/callback"
+
+# Where credentials are stored (optional): keyring (the default), file, env or memory.
+# The file backend encrypts credentials with MPESA_CREDENTIAL_PASSPHRASE; the env
+# backend reads MPESA_CONSUMER_KEY, MPESA_CONSUMER_SECRET, MPESA_PASSKEY, ...
+# Can also be set with MPESA_CREDENTIAL_BACKEND.
+# credential_backend: file
+# credential_file: "/path/to/credentials.enc"
+
+# External program asked for credentials before the backend above (optional). It is
+# run with get, store or erase and exchanges key=value lines on stdin/stdout.
+# credential_helper: my-vault-helper
+
+# Named profiles (optional). Each profile overrides the values above and has its
+# own credentials; select one with --profile, MPESA_PROFILE or default_profile.
+# default_profile: paybill
+# profiles:
+#   paybill:
+#     environment: production
+#     business_shortcode: "123456"
+#     security_credential: "your-encrypted-credential"
 `
 
 	return os.WriteFile(filepath, []byte(configTemplate), 0600)
@@ -0,0 +1,110 @@
+package mpesa
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"embed"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// bundledCertificates holds the Daraja public certificates embedded at build
+// time, named after their environment (see certs/README.md)
+//
+//go:embed certs
+var bundledCertificates embed.FS
+
+// ErrNoInitiatorPassword is returned when no initiator password is stored for a profile.
+var ErrNoInitiatorPassword = errors.New("no initiator password stored")
+
+// LoadCertificate returns the public certificate used to encrypt security credentials
+// for environment. A non-empty path is read instead of the bundled certificate.
+func LoadCertificate(environment, path string) (*x509.Certificate, error) {
+	var data []byte
+	var err error
+	if path != "" {
+		data, err = os.ReadFile(path) // #nosec G304 - path is chosen by the user
+		if err != nil {
+			return nil, fmt.Errorf("failed to read certificate: %w", err)
+		}
+	} else {
+		data, err = bundledCertificates.ReadFile("certs/" + environment + ".cer")
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("no %s certificate is bundled with this build; download it from the Daraja portal and set certificate_path or --cert", environment)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundled certificate: %w", err)
+		}
+	}
+
+	return ParseCertificate(data)
+}
+
+// ParseCertificate decodes a PEM or DER encoded X.509 certificate with an RSA public key.
+func ParseCertificate(data []byte) (*x509.Certificate, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		data = block.Bytes
+	}
+
+	cert, err := x509.ParseCertificate(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	if _, ok := cert.PublicKey.(*rsa.PublicKey); !ok {
+		return nil, fmt.Errorf("certificate of %s does not hold an RSA public key", cert.Subject)
+	}
+
+	return cert, nil
+}
+
+// EncryptSecurityCredential encrypts the initiator password with the public key of cert
+// using RSA PKCS#1 v1.5 and returns it base64 encoded, as M-Pesa expects the SecurityCredential.
+// The result differs on every call because of the random padding.
+func EncryptSecurityCredential(password string, cert *x509.Certificate) (string, error) {
+	if password == "" {
+		return "", fmt.Errorf("initiator password cannot be empty")
+	}
+
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return "", fmt.Errorf("certificate of %s does not hold an RSA public key", cert.Subject)
+	}
+
+	encrypted, err := rsa.EncryptPKCS1v15(rand.Reader, publicKey, []byte(password))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt initiator password: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+// ResolveSecurityCredential sets config.SecurityCredential from the initiator password
+// stored for config.Profile, encrypted with the certificate of config.Environment or
+// config.CertificatePath. The configuration is left unchanged if no password is stored.
+func ResolveSecurityCredential(config *Config) error {
+	password, err := GetProfileInitiatorPassword(config.Profile)
+	if errors.Is(err, ErrNoInitiatorPassword) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	cert, err := LoadCertificate(config.Environment, config.CertificatePath)
+	if err != nil {
+		return err
+	}
+
+	credential, err := EncryptSecurityCredential(password, cert)
+	if err != nil {
+		return err
+	}
+
+	config.SecurityCredential = credential
+	return nil
+}
@@ -0,0 +1,174 @@
+package mpesa
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	keyring "github.com/zalando/go-keyring"
+)
+
+// testCertificate writes a self-signed certificate to a temporary PEM file and returns its path and private key
+func testCertificate(t *testing.T) (string, *rsa.PrivateKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "apicrypt.safaricom.co.ke"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(365 * 24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "cert.cer")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("failed to write certificate: %v", err)
+	}
+	return path, key
+}
+
+// TestEncryptSecurityCredential tests that the credential decrypts back to the initiator password
+func TestEncryptSecurityCredential(t *testing.T) {
+	path, key := testCertificate(t)
+
+	cert, err := LoadCertificate("sandbox", path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cert.Subject.CommonName != "apicrypt.safaricom.co.ke" {
+		t.Errorf("unexpected subject %s", cert.Subject)
+	}
+
+	credential, err := EncryptSecurityCredential("Safaricom999!*!", cert)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	encrypted, err := base64.StdEncoding.DecodeString(credential)
+	if err != nil {
+		t.Fatalf("expected base64 credential, got %v", err)
+	}
+	plain, err := rsa.DecryptPKCS1v15(nil, key, encrypted)
+	if err != nil || string(plain) != "Safaricom999!*!" {
+		t.Errorf("expected credential to decrypt to the password, got %q %v", plain, err)
+	}
+
+	if _, err := EncryptSecurityCredential("", cert); err == nil {
+		t.Error("expected error for empty password")
+	}
+
+	// DER encoded certificates are accepted as well
+	if _, err := ParseCertificate(cert.Raw); err != nil {
+		t.Errorf("expected DER certificate to parse, got %v", err)
+	}
+	if _, err := ParseCertificate([]byte("not a certificate")); err == nil {
+		t.Error("expected error for invalid certificate")
+	}
+}
+
+// TestLoadCertificateNotBundled tests the error for environments without a bundled certificate
+func TestLoadCertificateNotBundled(t *testing.T) {
+	_, err := LoadCertificate("staging", "")
+	if err == nil || !strings.Contains(err.Error(), "certificate_path") {
+		t.Errorf("expected hint about certificate_path, got %v", err)
+	}
+}
+
+// TestResolveSecurityCredential tests generating the credential from a stored initiator password
+func TestResolveSecurityCredential(t *testing.T) {
+	keyring.MockInit()
+	path, key := testCertificate(t)
+
+	config := &Config{Environment: "production", Profile: "paybill", CertificatePath: path, SecurityCredential: "configured"}
+	if err := ResolveSecurityCredential(config); err != nil || config.SecurityCredential != "configured" {
+		t.Errorf("expected configuration unchanged without stored password, got %q %v", config.SecurityCredential, err)
+	}
+	if err := validateConfig(&Config{Environment: "production", BusinessShortcode: "123456", Profile: "paybill"}); err == nil {
+		t.Error("expected production config without credential to be invalid")
+	}
+
+	if err := SetProfileInitiatorPassword("paybill", "secret-password"); err != nil {
+		t.Fatalf("failed to store initiator password: %v", err)
+	}
+	if err := ResolveSecurityCredential(config); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	encrypted, _ := base64.StdEncoding.DecodeString(config.SecurityCredential)
+	if plain, err := rsa.DecryptPKCS1v15(nil, key, encrypted); err != nil || string(plain) != "secret-password" {
+		t.Errorf("expected generated credential, got %q %v", plain, err)
+	}
+	// Validation never queries the store; the credential is resolved when a client is built
+	if err := validateConfig(&Config{Environment: "production", BusinessShortcode: "123456", Profile: "paybill"}); err == nil {
+		t.Error("expected production config without credential to stay invalid")
+	}
+	if err := validateSettings(&Config{Environment: "production", BusinessShortcode: "123456", Profile: "paybill"}); err != nil {
+		t.Errorf("expected settings without credential to be valid, got %v", err)
+	}
+
+	if err := DeleteProfileCredentials("paybill"); err != nil {
+		t.Fatalf("failed to delete credentials: %v", err)
+	}
+	if _, err := GetProfileInitiatorPassword("paybill"); !errors.Is(err, ErrNoInitiatorPassword) {
+		t.Errorf("expected initiator password to be deleted, got %v", err)
+	}
+}
+
+// TestGetDefaultConfigSecurityCredential tests that the default configuration leaves the credential to ResolveSecurityCredential
+func TestGetDefaultConfigSecurityCredential(t *testing.T) {
+	SetCredentialStore(NewMemoryStore())
+	defer SetCredentialStore(nil)
+
+	if err := SetProfileInitiatorPassword(DefaultProfile, "Safaricom999!*!"); err != nil {
+		t.Fatalf("failed to store initiator password: %v", err)
+	}
+
+	config := GetDefaultConfig()
+	if config.SecurityCredential != "" {
+		t.Errorf("expected no security credential in the default configuration, got %q", config.SecurityCredential)
+	}
+
+	if err := ResolveSecurityCredential(config); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if config.SecurityCredential == "" {
+		t.Error("expected security credential generated from the stored initiator password")
+	}
+}
+
+// TestBundledCertificates tests that the certificates embedded in the build are the Daraja ones and still valid
+func TestBundledCertificates(t *testing.T) {
+	for _, environment := range []string{"sandbox", "production"} {
+		t.Run(environment, func(t *testing.T) {
+			if _, err := bundledCertificates.ReadFile("certs/" + environment + ".cer"); err != nil {
+				t.Fatalf("no %s certificate bundled (see certs/README.md)", environment)
+			}
+
+			cert, err := LoadCertificate(environment, "")
+			if err != nil {
+				t.Fatalf("expected bundled certificate to load, got %v", err)
+			}
+			if !strings.Contains(strings.ToLower(cert.Subject.String()), "safaricom") {
+				t.Errorf("expected a Safaricom certificate, got subject %s", cert.Subject)
+			}
+			if !time.Now().Before(cert.NotAfter) {
+				t.Errorf("bundled %s certificate expired on %s", environment, cert.NotAfter.Format("2006-01-02"))
+			}
+		})
+	}
+}
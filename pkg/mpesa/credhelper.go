@@ -0,0 +1,133 @@
+package mpesa
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// credentialHelperKey is the config file key naming an external credential helper
+	credentialHelperKey = "credential_helper"
+
+	// helperTimeout bounds a single invocation of a credential helper
+	helperTimeout = 30 * time.Second
+)
+
+// HelperStore obtains credentials from an external program, in the manner of git's
+// credential helpers, and falls back to another store for credentials the helper
+// does not have.
+//
+// The helper is run as "<command> get|store|erase" with a request on stdin: lines of
+// key=value pairs naming the account, profile and credential name (plus value for
+// store), ended by a blank line. For get, the helper answers with a "value=..." line
+// on stdout, or prints nothing if it has no value. A non-zero exit status is an error.
+// The helper's stderr is passed through, so it can report problems or prompt.
+//
+// Reads fall back to the other store when the helper has no value or fails. Writes
+// go to the helper only, so that secrets it manages are not copied elsewhere, while
+// erasing reaches both stores, so that nothing stored before the helper was set up
+// survives a logout.
+type HelperStore struct {
+	command  []string
+	fallback CredentialStore
+}
+
+// NewHelperStore returns a store that runs command, split into program and arguments
+// on whitespace, and falls back to fallback. fallback may be nil.
+func NewHelperStore(command string, fallback CredentialStore) *HelperStore {
+	return &HelperStore{command: strings.Fields(command), fallback: fallback}
+}
+
+// Name describes the helper and its fallback.
+func (s *HelperStore) Name() string {
+	name := fmt.Sprintf("credential helper '%s'", strings.Join(s.command, " "))
+	if s.fallback != nil {
+		name += " (fallback: " + s.fallback.Name() + ")"
+	}
+	return name
+}
+
+// Get returns the value the helper has for account, or else the fallback's value.
+// If the helper fails, its error is returned only when the fallback has no value either.
+func (s *HelperStore) Get(account string) (string, error) {
+	out, err := s.run("get", account, "")
+	if err != nil {
+		if s.fallback != nil {
+			if value, fallbackErr := s.fallback.Get(account); fallbackErr == nil {
+				return value, nil
+			}
+		}
+		return "", err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "value="); ok && value != "" {
+			return value, nil
+		}
+	}
+
+	if s.fallback == nil {
+		return "", ErrCredentialNotFound
+	}
+	return s.fallback.Get(account)
+}
+
+// Set stores value for account with the helper only, so that secrets managed by the
+// helper are not copied into the fallback store.
+func (s *HelperStore) Set(account, value string) error {
+	_, err := s.run("store", account, value)
+	return err
+}
+
+// Delete erases account from the helper and from the fallback store. The fallback
+// is cleared even if the helper fails; the first error is returned.
+func (s *HelperStore) Delete(account string) error {
+	_, err := s.run("erase", account, "")
+	if s.fallback != nil {
+		if fallbackErr := s.fallback.Delete(account); err == nil {
+			err = fallbackErr
+		}
+	}
+	return err
+}
+
+// run invokes the helper with operation and returns its output.
+func (s *HelperStore) run(operation, account, value string) ([]byte, error) {
+	if len(s.command) == 0 {
+		return nil, fmt.Errorf("credential_helper is empty")
+	}
+
+	var request strings.Builder
+	profile, name := DefaultProfile, account
+	if before, after, ok := strings.Cut(account, "/"); ok {
+		profile, name = before, after
+	}
+	fmt.Fprintf(&request, "account=%s\nprofile=%s\nname=%s\n", account, profile, name)
+	if operation == "store" {
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("credential values cannot contain line breaks")
+		}
+		fmt.Fprintf(&request, "value=%s\n", value)
+	}
+	request.WriteString("\n")
+
+	ctx, cancel := context.WithTimeout(context.Background(), helperTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, s.command[0], append(s.command[1:], operation)...) // #nosec G204 - the helper is configured by the user
+	cmd.Stdin = strings.NewReader(request.String())
+	cmd.Stderr = os.Stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("credential helper '%s %s' failed: %w", s.command[0], operation, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,101 @@
+package mpesa
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// testHelper writes a credential helper script that keeps its values in a directory and logs its requests
+func testHelper(t *testing.T) (string, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("credential helper tests use a shell script")
+	}
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "helper")
+	content := `#!/bin/sh
+dir="$(dirname "$0")"
+request="$(cat)"
+echo "$1" >> "$dir/log"
+echo "$request" >> "$dir/log"
+account="$(echo "$request" | sed -n 's/^account=//p' | tr / _)"
+case "$1" in
+get) [ -f "$dir/$account" ] && echo "value=$(cat "$dir/$account")" ;;
+store) echo "$request" | sed -n 's/^value=//p' > "$dir/$account" ;;
+erase) rm -f "$dir/$account" ;;
+esac
+exit 0
+`
+	if err := os.WriteFile(script, []byte(content), 0700); err != nil {
+		t.Fatalf("failed to write helper: %v", err)
+	}
+	return script, dir
+}
+
+// TestHelperStore tests the get, store and erase protocol and the fallback store
+func TestHelperStore(t *testing.T) {
+	script, dir := testHelper(t)
+	fallback := NewMemoryStore()
+	_ = fallback.Set("passkey", "from-fallback")
+
+	store := NewHelperStore(script, fallback)
+	if !strings.Contains(store.Name(), "fallback: memory") {
+		t.Errorf("unexpected name %s", store.Name())
+	}
+
+	if err := store.Set("paybill/consumer_key", "vault-key"); err != nil {
+		t.Fatalf("failed to store credential: %v", err)
+	}
+	if _, err := fallback.Get("paybill/consumer_key"); !errors.Is(err, ErrCredentialNotFound) {
+		t.Error("expected stored credential not to be copied to the fallback")
+	}
+	if value, err := store.Get("paybill/consumer_key"); err != nil || value != "vault-key" {
+		t.Errorf("expected value from helper, got %q %v", value, err)
+	}
+	if value, err := store.Get("passkey"); err != nil || value != "from-fallback" {
+		t.Errorf("expected value from fallback, got %q %v", value, err)
+	}
+
+	log, _ := os.ReadFile(filepath.Join(dir, "log"))
+	if !strings.Contains(string(log), "profile=paybill\nname=consumer_key\nvalue=vault-key") {
+		t.Errorf("unexpected helper requests:\n%s", log)
+	}
+
+	if err := store.Delete("passkey"); err != nil {
+		t.Fatalf("failed to erase credential: %v", err)
+	}
+	if _, err := store.Get("passkey"); !errors.Is(err, ErrCredentialNotFound) {
+		t.Errorf("expected erase to reach the fallback, got %v", err)
+	}
+}
+
+// TestHelperStoreFailure tests that reads fall back when the helper exits non-zero
+func TestHelperStoreFailure(t *testing.T) {
+	fallback := NewMemoryStore()
+	_ = fallback.Set("consumer_key", "from-fallback")
+
+	store := NewHelperStore("false", fallback)
+	if value, err := store.Get("consumer_key"); err != nil || value != "from-fallback" {
+		t.Errorf("expected value from fallback, got %q %v", value, err)
+	}
+	if _, err := store.Get("passkey"); err == nil || !strings.Contains(err.Error(), "credential helper") {
+		t.Errorf("expected helper error when the fallback has no value, got %v", err)
+	}
+
+	if err := store.Delete("consumer_key"); err == nil {
+		t.Error("expected helper error on erase")
+	}
+	if _, err := fallback.Get("consumer_key"); !errors.Is(err, ErrCredentialNotFound) {
+		t.Errorf("expected erase to reach the fallback despite the helper failing, got %v", err)
+	}
+
+	missing := NewHelperStore(filepath.Join(t.TempDir(), "missing"), nil)
+	if err := missing.Set("consumer_key", "x"); err == nil {
+		t.Error("expected error for missing helper")
+	}
+}
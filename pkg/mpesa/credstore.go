@@ -0,0 +1,406 @@
+package mpesa
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+	keyring "github.com/zalando/go-keyring"
+)
+
+// Names of the credential backends, as set with credential_backend or MPESA_CREDENTIAL_BACKEND
+const (
+	// BackendKeyring stores credentials in the operating system's keychain (the default)
+	BackendKeyring = "keyring"
+
+	// BackendFile stores credentials in a file encrypted with a passphrase
+	BackendFile = "file"
+
+	// BackendEnv reads credentials from environment variables
+	BackendEnv = "env"
+
+	// BackendMemory keeps credentials in memory for the lifetime of the process
+	BackendMemory = "memory"
+)
+
+const (
+	// credentialBackendKey is the config file key selecting the credential backend
+	credentialBackendKey = "credential_backend"
+
+	// credentialFileKey is the config file key overriding the path of the file backend
+	credentialFileKey = "credential_file"
+
+	// passphraseEnv holds the passphrase of the file backend
+	passphraseEnv = envPrefix + "_CREDENTIAL_PASSPHRASE"
+
+	// pbkdf2Iterations is the PBKDF2-SHA256 work factor of the file backend's key
+	pbkdf2Iterations = 600000
+)
+
+// ErrCredentialNotFound is returned by a CredentialStore for an account it holds no value for.
+var ErrCredentialNotFound = errors.New("credential not found")
+
+// CredentialStore keeps secret values, such as consumer keys, under account names.
+type CredentialStore interface {
+	// Name returns the name of the backend, e.g. BackendKeyring
+	Name() string
+
+	// Get returns the value stored for account, or ErrCredentialNotFound
+	Get(account string) (string, error)
+
+	// Set stores value for account, replacing any previous value
+	Set(account, value string) error
+
+	// Delete removes the value of account; it is not an error if there is none
+	Delete(account string) error
+}
+
+// The store used by the credential functions of this package
+var (
+	storeMu     sync.Mutex
+	activeStore CredentialStore
+)
+
+// SetCredentialStore replaces the store used by the credential functions of this
+// package, e.g. with a MemoryStore in tests. Passing nil restores the configured backend.
+func SetCredentialStore(store CredentialStore) {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	activeStore = store
+}
+
+// ActiveCredentialStore returns the store used by the credential functions of this
+// package: the one set with SetCredentialStore, or else the backend selected with
+// MPESA_CREDENTIAL_BACKEND or credential_backend in the config file (keyring by default).
+// If credential_helper (or MPESA_CREDENTIAL_HELPER) names a helper, it is consulted
+// first and the backend serves as its fallback; see HelperStore.
+func ActiveCredentialStore() (CredentialStore, error) {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+
+	if activeStore != nil {
+		return activeStore, nil
+	}
+
+	if err := readConfig(); err != nil {
+		return nil, err
+	}
+
+	store, err := NewCredentialStore(viper.GetString(credentialBackendKey))
+	if err != nil {
+		return nil, err
+	}
+	if helper := viper.GetString(credentialHelperKey); helper != "" {
+		store = NewHelperStore(helper, store)
+	}
+	activeStore = store
+	return store, nil
+}
+
+// NewCredentialStore opens the named backend. An empty name selects BackendKeyring.
+// The file backend takes its passphrase from MPESA_CREDENTIAL_PASSPHRASE and its
+// location from credential_file, defaulting to credentials.enc in the config directory.
+func NewCredentialStore(backend string) (CredentialStore, error) {
+	switch backend {
+	case "", BackendKeyring:
+		return KeyringStore{}, nil
+	case BackendEnv:
+		return EnvStore{}, nil
+	case BackendMemory:
+		return NewMemoryStore(), nil
+	case BackendFile:
+		passphrase := os.Getenv(passphraseEnv)
+		if passphrase == "" {
+			return nil, fmt.Errorf("%s must be set to use the %s credential backend", passphraseEnv, BackendFile)
+		}
+
+		path := viper.GetString(credentialFileKey)
+		if path == "" {
+			dir, err := stateDir()
+			if err != nil {
+				return nil, err
+			}
+			path = filepath.Join(dir, "credentials.enc")
+		}
+		return NewFileStore(path, passphrase), nil
+	default:
+		return nil, fmt.Errorf("credential backend must be %s, %s, %s or %s, got: %s", BackendKeyring, BackendFile, BackendEnv, BackendMemory, backend)
+	}
+}
+
+// KeyringStore keeps credentials in the operating system's keychain (Keychain on
+// macOS, Credential Manager on Windows, Secret Service on Linux).
+type KeyringStore struct{}
+
+// Name returns BackendKeyring.
+func (KeyringStore) Name() string { return BackendKeyring }
+
+// Get returns the value stored for account in the keychain.
+func (KeyringStore) Get(account string) (string, error) {
+	value, err := keyring.Get(serviceName, account)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrCredentialNotFound
+	}
+	return value, err
+}
+
+// Set stores value for account in the keychain.
+func (KeyringStore) Set(account, value string) error {
+	return keyring.Set(serviceName, account, value)
+}
+
+// Delete removes account from the keychain.
+func (KeyringStore) Delete(account string) error {
+	err := keyring.Delete(serviceName, account)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// EnvStore reads credentials from environment variables named after the account:
+// MPESA_CONSUMER_KEY for the default profile's consumer_key, MPESA_PAYBILL_CONSUMER_KEY
+// for the paybill profile's. It is read-only.
+type EnvStore struct{}
+
+// Name returns BackendEnv.
+func (EnvStore) Name() string { return BackendEnv }
+
+// Get returns the value of the environment variable of account.
+func (EnvStore) Get(account string) (string, error) {
+	value := os.Getenv(EnvStoreVariable(account))
+	if value == "" {
+		return "", ErrCredentialNotFound
+	}
+	return value, nil
+}
+
+// Set fails: environment variables have to be set outside the CLI.
+func (EnvStore) Set(account, value string) error {
+	return fmt.Errorf("the %s credential backend is read-only; set %s instead", BackendEnv, EnvStoreVariable(account))
+}
+
+// Delete fails: environment variables have to be unset outside the CLI.
+func (EnvStore) Delete(account string) error {
+	return fmt.Errorf("the %s credential backend is read-only; unset %s instead", BackendEnv, EnvStoreVariable(account))
+}
+
+// EnvStoreVariable returns the environment variable EnvStore reads account from.
+func EnvStoreVariable(account string) string {
+	name := strings.NewReplacer("/", "_", "-", "_").Replace(account)
+	return envPrefix + "_" + strings.ToUpper(name)
+}
+
+// MemoryStore keeps credentials in memory, e.g. for tests.
+type MemoryStore struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{values: map[string]string{}}
+}
+
+// Name returns BackendMemory.
+func (s *MemoryStore) Name() string { return BackendMemory }
+
+// Get returns the value stored for account.
+func (s *MemoryStore) Get(account string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.values[account]
+	if !ok {
+		return "", ErrCredentialNotFound
+	}
+	return value, nil
+}
+
+// Set stores value for account.
+func (s *MemoryStore) Set(account, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[account] = value
+	return nil
+}
+
+// Delete removes account.
+func (s *MemoryStore) Delete(account string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.values, account)
+	return nil
+}
+
+// credentialFile is the on-disk format of FileStore. The accounts are encrypted as a
+// JSON object with AES-256-GCM, using a key derived from the passphrase with PBKDF2.
+type credentialFile struct {
+	Salt  []byte `json:"salt"`
+	Nonce []byte `json:"nonce"`
+	Data  []byte `json:"data"`
+}
+
+// FileStore keeps credentials in a file readable only by the current user,
+// encrypted with a key derived from a passphrase. It suits machines without a keychain.
+type FileStore struct {
+	path       string
+	passphrase string
+
+	mu   sync.Mutex
+	salt []byte
+	key  []byte
+}
+
+// NewFileStore returns a FileStore backed by the file at path and encrypted with passphrase.
+// The file is created on the first Set.
+func NewFileStore(path, passphrase string) *FileStore {
+	return &FileStore{path: path, passphrase: passphrase}
+}
+
+// Name returns BackendFile.
+func (s *FileStore) Name() string { return BackendFile }
+
+// Get returns the value stored for account.
+func (s *FileStore) Get(account string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, _, err := s.load()
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := values[account]
+	if !ok {
+		return "", ErrCredentialNotFound
+	}
+	return value, nil
+}
+
+// Set stores value for account.
+func (s *FileStore) Set(account, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, salt, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	values[account] = value
+	return s.save(values, salt)
+}
+
+// Delete removes account.
+func (s *FileStore) Delete(account string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, salt, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := values[account]; !ok {
+		return nil
+	}
+
+	delete(values, account)
+	return s.save(values, salt)
+}
+
+// load decrypts the credentials file and returns its accounts and salt.
+// A missing file holds no accounts and gets a new salt.
+func (s *FileStore) load() (map[string]string, []byte, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, nil, err
+		}
+		return map[string]string{}, salt, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	var file credentialFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse credentials file: %w", err)
+	}
+
+	gcm, err := s.cipher(file.Salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(file.Nonce) != gcm.NonceSize() {
+		return nil, nil, fmt.Errorf("credentials file %s is corrupted", s.path)
+	}
+	plain, err := gcm.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt credentials file %s: wrong passphrase or corrupted file", s.path)
+	}
+
+	values := map[string]string{}
+	if err := json.Unmarshal(plain, &values); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse credentials file: %w", err)
+	}
+	return values, file.Salt, nil
+}
+
+// save encrypts values with a fresh nonce and atomically replaces the credentials file.
+func (s *FileStore) save(values map[string]string, salt []byte) error {
+	plain, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials: %w", err)
+	}
+
+	gcm, err := s.cipher(salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(credentialFile{Salt: salt, Nonce: nonce, Data: gcm.Seal(nil, nonce, plain, nil)}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials file: %w", err)
+	}
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	return nil
+}
+
+// cipher returns the AES-GCM cipher keyed with the passphrase and salt. The derived
+// key is kept, since deriving it is deliberately slow.
+func (s *FileStore) cipher(salt []byte) (cipher.AEAD, error) {
+	if s.key == nil || string(s.salt) != string(salt) {
+		key, err := pbkdf2.Key(sha256.New, s.passphrase, salt, pbkdf2Iterations, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive credentials key: %w", err)
+		}
+		s.salt, s.key = salt, key
+	}
+
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
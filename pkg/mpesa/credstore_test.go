@@ -0,0 +1,184 @@
+package mpesa
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFileStore tests that the file backend encrypts credentials in a private file
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+	store := NewFileStore(path, "correct horse")
+
+	if _, err := store.Get("consumer_key"); !errors.Is(err, ErrCredentialNotFound) {
+		t.Errorf("expected not found before the file exists, got %v", err)
+	}
+	if err := store.Set("consumer_key", "my-key"); err != nil {
+		t.Fatalf("failed to set credential: %v", err)
+	}
+	if err := store.Set("paybill/consumer_key", "paybill-key"); err != nil {
+		t.Fatalf("failed to set credential: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("expected credentials file, got %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected mode 0600, got %v", info.Mode().Perm())
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "my-key") {
+		t.Error("expected credentials to be encrypted on disk")
+	}
+
+	// A new store with the same passphrase reads what the first one wrote
+	reopened := NewFileStore(path, "correct horse")
+	if value, err := reopened.Get("paybill/consumer_key"); err != nil || value != "paybill-key" {
+		t.Errorf("expected stored credential, got %q %v", value, err)
+	}
+	if err := reopened.Delete("consumer_key"); err != nil {
+		t.Fatalf("failed to delete credential: %v", err)
+	}
+	if _, err := reopened.Get("consumer_key"); !errors.Is(err, ErrCredentialNotFound) {
+		t.Errorf("expected deleted credential to be gone, got %v", err)
+	}
+	if err := reopened.Delete("consumer_key"); err != nil {
+		t.Errorf("expected deleting a missing credential to succeed, got %v", err)
+	}
+
+	if _, err := NewFileStore(path, "wrong").Get("paybill/consumer_key"); err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("expected wrong passphrase error, got %v", err)
+	}
+}
+
+// TestEnvStore tests reading credentials from environment variables
+func TestEnvStore(t *testing.T) {
+	t.Setenv("MPESA_CONSUMER_KEY", "env-key")
+	t.Setenv("MPESA_PAYBILL_CONSUMER_SECRET", "env-secret")
+
+	store := EnvStore{}
+	if value, err := store.Get("consumer_key"); err != nil || value != "env-key" {
+		t.Errorf("expected default profile key, got %q %v", value, err)
+	}
+	if value, err := store.Get("paybill/consumer_secret"); err != nil || value != "env-secret" {
+		t.Errorf("expected paybill profile secret, got %q %v", value, err)
+	}
+	if _, err := store.Get("passkey"); !errors.Is(err, ErrCredentialNotFound) {
+		t.Errorf("expected not found for unset variable, got %v", err)
+	}
+	if err := store.Set("consumer_key", "x"); err == nil || !strings.Contains(err.Error(), "MPESA_CONSUMER_KEY") {
+		t.Errorf("expected read-only error naming the variable, got %v", err)
+	}
+	if got := EnvStoreVariable("my-app/initiator_password"); got != "MPESA_MY_APP_INITIATOR_PASSWORD" {
+		t.Errorf("unexpected variable name %s", got)
+	}
+}
+
+// TestNewCredentialStore tests backend selection
+func TestNewCredentialStore(t *testing.T) {
+	for backend, expected := range map[string]string{"": BackendKeyring, "keyring": BackendKeyring, "env": BackendEnv, "memory": BackendMemory} {
+		store, err := NewCredentialStore(backend)
+		if err != nil || store.Name() != expected {
+			t.Errorf("backend %q: expected %s, got %v %v", backend, expected, store, err)
+		}
+	}
+
+	t.Setenv(passphraseEnv, "")
+	if _, err := NewCredentialStore(BackendFile); err == nil || !strings.Contains(err.Error(), passphraseEnv) {
+		t.Errorf("expected passphrase error, got %v", err)
+	}
+	t.Setenv(passphraseEnv, "secret")
+	if store, err := NewCredentialStore(BackendFile); err != nil || store.Name() != BackendFile {
+		t.Errorf("expected file backend, got %v %v", store, err)
+	}
+
+	if _, err := NewCredentialStore("vault"); err == nil {
+		t.Error("expected error for unknown backend")
+	}
+}
+
+// TestSetCredentialStore tests that the profile credential functions use the active store
+func TestSetCredentialStore(t *testing.T) {
+	memory := NewMemoryStore()
+	SetCredentialStore(memory)
+	defer SetCredentialStore(nil)
+
+	if err := SetProfileCredentials("paybill", "key", "secret"); err != nil {
+		t.Fatalf("failed to set credentials: %v", err)
+	}
+	if value, err := memory.Get("paybill/consumer_key"); err != nil || value != "key" {
+		t.Errorf("expected credentials in memory store, got %q %v", value, err)
+	}
+
+	store, err := ActiveCredentialStore()
+	if err != nil || store.Name() != BackendMemory {
+		t.Errorf("expected memory store to be active, got %v %v", store, err)
+	}
+}
+
+// TestGetProfileCredentialStatus tests that the status reports the stored credentials only
+func TestGetProfileCredentialStatus(t *testing.T) {
+	SetCredentialStore(NewMemoryStore())
+	defer SetCredentialStore(nil)
+
+	if err := SetProfileCredentials("paybill", "key", "secret"); err != nil {
+		t.Fatalf("failed to set credentials: %v", err)
+	}
+
+	status, err := GetProfileCredentialStatus("paybill")
+	if err != nil {
+		t.Fatalf("failed to get status: %v", err)
+	}
+	if status.ConsumerKey != "key" || !status.ConsumerSecret || status.Passkey || status.InitiatorPassword {
+		t.Errorf("unexpected status: %+v", status)
+	}
+
+	if status, err := GetProfileCredentialStatus("other"); err != nil || status.ConsumerKey != "" || status.ConsumerSecret {
+		t.Errorf("expected empty status for other profile, got %+v %v", status, err)
+	}
+}
+
+// failingStore is a memory store whose Set fails once failSet reaches zero
+type failingStore struct {
+	*MemoryStore
+	failSet int
+}
+
+func (s *failingStore) Set(account, value string) error {
+	s.failSet--
+	if s.failSet == 0 {
+		return errors.New("store unavailable")
+	}
+	return s.MemoryStore.Set(account, value)
+}
+
+// TestRotateProfileCredentials tests that a failed rotation restores the previous credentials
+func TestRotateProfileCredentials(t *testing.T) {
+	store := &failingStore{MemoryStore: NewMemoryStore()}
+	SetCredentialStore(store)
+	defer SetCredentialStore(nil)
+
+	if err := SetProfileCredentials("default", "old-key", "old-secret"); err != nil {
+		t.Fatalf("failed to set credentials: %v", err)
+	}
+
+	// The secret is stored after the key, so the second write fails
+	store.failSet = 2
+	if err := RotateProfileCredentials("default", "new-key", "new-secret"); err == nil {
+		t.Fatal("expected rotation to fail")
+	}
+	if key, secret, err := GetProfileCredentials("default"); err != nil || key != "old-key" || secret != "old-secret" {
+		t.Errorf("expected previous credentials to be restored, got %q %q %v", key, secret, err)
+	}
+
+	if err := RotateProfileCredentials("default", "new-key", "new-secret"); err != nil {
+		t.Fatalf("failed to rotate credentials: %v", err)
+	}
+	if key, secret, err := GetProfileCredentials("default"); err != nil || key != "new-key" || secret != "new-secret" {
+		t.Errorf("expected new credentials, got %q %q %v", key, secret, err)
+	}
+}
@@ -0,0 +1,103 @@
+package mpesa
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// ErrResultTimeout is returned when no matching result arrives in time.
+var ErrResultTimeout = errors.New("timed out waiting for the result")
+
+// maxResultBodySize limits the size of result bodies accepted by a ResultListener
+const maxResultBodySize = 1 << 20
+
+// ResultListener is a temporary local HTTP server that receives the results M-Pesa
+// posts to the ResultURL and QueueTimeOutURL. M-Pesa must be able to reach it,
+// typically through a tunnel whose public URL is used as ResultURL.
+type ResultListener struct {
+	listener net.Listener
+	server   *http.Server
+	results  chan *Result
+}
+
+// ListenForResults starts a ResultListener on addr, e.g. ":8080".
+// Results posted to any path are accepted.
+func ListenForResults(addr string) (*ResultListener, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	l := &ResultListener{
+		listener: listener,
+		results:  make(chan *Result, 16),
+	}
+	l.server = &http.Server{Handler: http.HandlerFunc(l.handle), ReadHeaderTimeout: 10 * time.Second}
+
+	go func() { _ = l.server.Serve(listener) }()
+
+	return l, nil
+}
+
+// Addr returns the address the listener accepts connections on.
+func (l *ResultListener) Addr() string {
+	return l.listener.Addr().String()
+}
+
+// handle accepts a result and acknowledges it the way M-Pesa expects.
+func (l *ResultListener) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxResultBodySize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := ParseResult(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Results nobody waits for are dropped rather than blocking M-Pesa
+	select {
+	case l.results <- result:
+	default:
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"ResultCode":0,"ResultDesc":"Accepted"}`))
+}
+
+// WaitFor returns the first result for the request acknowledged with the given
+// ConversationID and OriginatorConversationID; a result matching either is accepted
+// and an empty ID matches nothing. Other results are discarded.
+func (l *ResultListener) WaitFor(conversationID, originatorConversationID string, timeout time.Duration) (*Result, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		select {
+		case result := <-l.results:
+			if (conversationID != "" && result.ConversationID == conversationID) ||
+				(originatorConversationID != "" && result.OriginatorConversationID == originatorConversationID) {
+				return result, nil
+			}
+		case <-deadline.C:
+			return nil, ErrResultTimeout
+		}
+	}
+}
+
+// Close stops the listener.
+func (l *ResultListener) Close() error {
+	return l.server.Close()
+}
@@ -0,0 +1,61 @@
+package mpesa
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestResultListener tests receiving results and matching them by conversation ID
+func TestResultListener(t *testing.T) {
+	listener, err := ListenForResults("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start listener: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	post := func(body string) {
+		resp, err := http.Post("http://"+listener.Addr()+"/result", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("failed to post result: %v", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected 200, got %d", resp.StatusCode)
+		}
+	}
+
+	post(`{"Result":{"ResultCode":0,"ConversationID":"AG_other","OriginatorConversationID":"other"}}`)
+	post(`{"Result":{"ResultCode":0,"ConversationID":"AG_wanted","OriginatorConversationID":"wanted"}}`)
+	post(`{"Result":{"ResultCode":0,"OriginatorConversationID":"by-originator"}}`)
+	post(`{"Result":{"ResultCode":"SFC_IC0003","ResultDesc":"Operator does not exist.","ConversationID":"AG_failed"}}`)
+
+	result, err := listener.WaitFor("AG_wanted", "", time.Second)
+	if err != nil {
+		t.Fatalf("expected result, got %v", err)
+	}
+	if result.OriginatorConversationID != "wanted" {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	result, err = listener.WaitFor("AG_unknown", "by-originator", time.Second)
+	if err != nil {
+		t.Fatalf("expected result matched by originator conversation ID, got %v", err)
+	}
+	if result.ConversationID != "" {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	// Results with alphanumeric codes are delivered rather than rejected
+	result, err = listener.WaitFor("AG_failed", "", time.Second)
+	if err != nil || result.ResultCode.String() != "SFC_IC0003" {
+		t.Errorf("expected failed result, got %+v %v", result, err)
+	}
+
+	_, err = listener.WaitFor("AG_missing", "", 50*time.Millisecond)
+	if !errors.Is(err, ErrResultTimeout) {
+		t.Errorf("expected ErrResultTimeout, got %v", err)
+	}
+}
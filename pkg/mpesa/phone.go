@@ -0,0 +1,31 @@
+package mpesa
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// msisdnPattern matches a Kenyan mobile number in the 2547XXXXXXXX/2541XXXXXXXX form Daraja expects
+var msisdnPattern = regexp.MustCompile(`^254[17][0-9]{8}$`)
+
+// NormalizePhoneNumber converts a Kenyan mobile number written as 07XXXXXXXX,
+// 7XXXXXXXX, +2547XXXXXXXX or 2547XXXXXXXX (and the 01 equivalents) into the
+// 2547XXXXXXXX form used by the M-Pesa API.
+func NormalizePhoneNumber(phone string) (string, error) {
+	normalized := strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(phone))
+	normalized = strings.TrimPrefix(normalized, "+")
+
+	switch {
+	case strings.HasPrefix(normalized, "0") && len(normalized) == 10:
+		normalized = "254" + normalized[1:]
+	case len(normalized) == 9:
+		normalized = "254" + normalized
+	}
+
+	if !msisdnPattern.MatchString(normalized) {
+		return "", fmt.Errorf("invalid phone number '%s': expected a Kenyan mobile number such as 0712345678 or 254712345678", phone)
+	}
+
+	return normalized, nil
+}
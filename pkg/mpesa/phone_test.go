@@ -0,0 +1,41 @@
+package mpesa
+
+import "testing"
+
+// TestNormalizePhoneNumber tests conversion of phone numbers to the API format
+func TestNormalizePhoneNumber(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  string
+		expectErr bool
+	}{
+		{input: "0712345678", expected: "254712345678"},
+		{input: "712345678", expected: "254712345678"},
+		{input: "+254712345678", expected: "254712345678"},
+		{input: "254712345678", expected: "254712345678"},
+		{input: "0110 123 456", expected: "254110123456"},
+		{input: "0712-345-678", expected: "254712345678"},
+		{input: "0212345678", expectErr: true},
+		{input: "25471234567", expectErr: true},
+		{input: "abc", expectErr: true},
+		{input: "", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NormalizePhoneNumber(tt.input)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got '%s'", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package mpesa
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+)
+
+// DefaultProfile is the profile used when none is selected. Its configuration is
+// the top-level config file values and its credentials are stored under the
+// keychain accounts used before profiles existed.
+const DefaultProfile = "default"
+
+const (
+	// profilesKey is the config file key holding the named profile blocks
+	profilesKey = "profiles"
+
+	// defaultProfileKey is the config file key naming the profile used when none is selected
+	defaultProfileKey = "default_profile"
+)
+
+// profileNamePattern restricts profile names to what is safe as a config file key
+// (viper lowercases keys) and as part of a keychain account name.
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidateProfileName checks that name can be used as a profile name.
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name '%s': use lowercase letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// ResolveProfile returns the profile to use. An explicit name (e.g. from --profile)
+// wins, followed by the MPESA_PROFILE environment variable, the default_profile
+// setting in the config file and finally DefaultProfile.
+func ResolveProfile(name string) string {
+	if name != "" {
+		return name
+	}
+
+	if name := os.Getenv(envPrefix + "_PROFILE"); name != "" {
+		return name
+	}
+
+	if err := readConfig(); err == nil {
+		if name := viper.GetString(defaultProfileKey); name != "" {
+			return name
+		}
+	}
+
+	return DefaultProfile
+}
+
+// ListProfiles returns the names of all configured profiles in alphabetical order.
+// DefaultProfile is always included since the top-level configuration belongs to it.
+func ListProfiles() ([]string, error) {
+	if err := readConfig(); err != nil {
+		return nil, err
+	}
+
+	names := []string{DefaultProfile}
+	for name := range viper.GetStringMap(profilesKey) {
+		if name != DefaultProfile {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names[1:])
+
+	return names, nil
+}
+
+// ProfileExists reports whether name is a configured profile.
+func ProfileExists(name string) (bool, error) {
+	names, err := ListProfiles()
+	if err != nil {
+		return false, err
+	}
+
+	for _, n := range names {
+		if n == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SaveProfile writes the non-empty values of config as the block of the named profile
+// in the config file, replacing any previous block for that profile.
+func SaveProfile(name string, config *Config) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	// The security credential may be generated from a stored initiator password later
+	if err := validateSettings(config); err != nil {
+		return err
+	}
+
+	return updateConfigFile(func(settings map[string]interface{}) {
+		profiles, _ := settings[profilesKey].(map[string]interface{})
+		if profiles == nil {
+			profiles = map[string]interface{}{}
+		}
+		profiles[name] = configValues(config)
+		settings[profilesKey] = profiles
+	})
+}
+
+// RemoveProfile deletes the block of the named profile from the config file and
+// clears default_profile if it pointed at it. Credentials are not touched; use
+// DeleteProfileCredentials for that.
+func RemoveProfile(name string) error {
+	return updateConfigFile(func(settings map[string]interface{}) {
+		if profiles, ok := settings[profilesKey].(map[string]interface{}); ok {
+			delete(profiles, name)
+			if len(profiles) == 0 {
+				delete(settings, profilesKey)
+			}
+		}
+		if settings[defaultProfileKey] == name {
+			delete(settings, defaultProfileKey)
+		}
+	})
+}
+
+// SetDefaultProfile records name as the default_profile in the config file.
+func SetDefaultProfile(name string) error {
+	return updateConfigFile(func(settings map[string]interface{}) {
+		if name == DefaultProfile {
+			delete(settings, defaultProfileKey)
+			return
+		}
+		settings[defaultProfileKey] = name
+	})
+}
+
+// ConfigFilePath returns the config file that profile changes are written to:
+// the config file in use, or ~/.config/mpesa-cli/mpesa-cli.yaml if there is none.
+func ConfigFilePath() (string, error) {
+	if err := readConfig(); err != nil {
+		return "", err
+	}
+
+	if path := viper.ConfigFileUsed(); path != "" {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "mpesa-cli", "mpesa-cli.yaml"), nil
+}
+
+// updateConfigFile applies update to the settings in the config file and writes them back.
+// Only the file's own contents are rewritten; defaults and environment variables are not
+// copied into it. Comments in the file are not preserved.
+func updateConfigFile(update func(settings map[string]interface{})) error {
+	path, err := ConfigFilePath()
+	if err != nil {
+		return err
+	}
+
+	settings := map[string]interface{}{}
+	data, err := os.ReadFile(path) // #nosec G304 - path is the user's own config file
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if settings == nil {
+		settings = map[string]interface{}{}
+	}
+
+	update(settings)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(settings); err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+	_ = encoder.Close()
+	data = buf.Bytes()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return nil
+}
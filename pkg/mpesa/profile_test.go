@@ -0,0 +1,241 @@
+package mpesa
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	keyring "github.com/zalando/go-keyring"
+)
+
+// useConfigFile points viper at a config file with the given content in a temp directory
+func useConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	path := filepath.Join(t.TempDir(), "mpesa-cli.yaml")
+	if content != "" {
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write config file: %v", err)
+		}
+	}
+	viper.SetConfigFile(path)
+
+	return path
+}
+
+const profilesConfig = `environment: sandbox
+business_shortcode: "600986"
+initiator: testapi
+profiles:
+  paybill:
+    environment: production
+    business_shortcode: "123456"
+    security_credential: prod-credential
+  till:
+    business_shortcode: "654321"
+`
+
+// TestGetProfileConfigMergesProfileBlock tests that profile values override top-level values
+func TestGetProfileConfigMergesProfileBlock(t *testing.T) {
+	useConfigFile(t, profilesConfig)
+	t.Setenv("MPESA_PROFILE", "")
+
+	config, err := GetProfileConfig("paybill")
+	if err != nil {
+		t.Fatalf("failed to get profile config: %v", err)
+	}
+
+	if config.Environment != "production" || config.BusinessShortcode != "123456" {
+		t.Errorf("expected profile values, got %+v", config)
+	}
+	if config.Initiator != "testapi" {
+		t.Errorf("expected top-level initiator to be inherited, got '%s'", config.Initiator)
+	}
+	if config.Profile != "paybill" {
+		t.Errorf("expected profile name 'paybill', got '%s'", config.Profile)
+	}
+
+	config, err = GetProfileConfig("till")
+	if err != nil {
+		t.Fatalf("failed to get profile config: %v", err)
+	}
+	if config.Environment != "sandbox" || config.BusinessShortcode != "654321" {
+		t.Errorf("expected till profile on sandbox, got %+v", config)
+	}
+
+	config, err = GetConfig()
+	if err != nil {
+		t.Fatalf("failed to get config: %v", err)
+	}
+	if config.Profile != DefaultProfile || config.BusinessShortcode != "600986" {
+		t.Errorf("expected default profile with top-level values, got %+v", config)
+	}
+}
+
+// TestGetProfileConfigEnvOverride tests that environment variables override profile values
+func TestGetProfileConfigEnvOverride(t *testing.T) {
+	useConfigFile(t, profilesConfig)
+	t.Setenv("MPESA_BUSINESS_SHORTCODE", "999999")
+
+	config, err := GetProfileConfig("paybill")
+	if err != nil {
+		t.Fatalf("failed to get profile config: %v", err)
+	}
+	if config.BusinessShortcode != "999999" {
+		t.Errorf("expected shortcode from environment, got '%s'", config.BusinessShortcode)
+	}
+}
+
+// TestGetProfileConfigUnknownProfile tests that selecting a missing profile is an error
+func TestGetProfileConfigUnknownProfile(t *testing.T) {
+	useConfigFile(t, profilesConfig)
+
+	_, err := GetProfileConfig("missing")
+	if err == nil || !strings.Contains(err.Error(), "profile 'missing' not found") {
+		t.Errorf("expected profile not found error, got %v", err)
+	}
+}
+
+// TestProfileEnvironment tests that the environment is read without validating the rest of the profile
+func TestProfileEnvironment(t *testing.T) {
+	useConfigFile(t, profilesConfig+`  incomplete:
+    environment: production
+    business_shortcode: ""
+  staging:
+    environment: staging
+`)
+
+	if _, err := GetProfileConfig("incomplete"); err == nil {
+		t.Fatal("expected incomplete production profile to fail full validation")
+	}
+	if env, err := ProfileEnvironment("incomplete"); err != nil || env != "production" {
+		t.Errorf("expected production, got %q %v", env, err)
+	}
+	if env, err := ProfileEnvironment("till"); err != nil || env != "sandbox" {
+		t.Errorf("expected sandbox, got %q %v", env, err)
+	}
+
+	if _, err := ProfileEnvironment("staging"); err == nil || !strings.Contains(err.Error(), "environment must be") {
+		t.Errorf("expected invalid environment error, got %v", err)
+	}
+	if _, err := ProfileEnvironment("missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected profile not found error, got %v", err)
+	}
+}
+
+// TestResolveProfile tests profile selection precedence
+func TestResolveProfile(t *testing.T) {
+	useConfigFile(t, profilesConfig+"default_profile: till\n")
+
+	t.Setenv("MPESA_PROFILE", "")
+	if got := ResolveProfile(""); got != "till" {
+		t.Errorf("expected default_profile 'till', got '%s'", got)
+	}
+
+	t.Setenv("MPESA_PROFILE", "paybill")
+	if got := ResolveProfile(""); got != "paybill" {
+		t.Errorf("expected MPESA_PROFILE 'paybill', got '%s'", got)
+	}
+
+	if got := ResolveProfile("flag"); got != "flag" {
+		t.Errorf("expected explicit profile 'flag', got '%s'", got)
+	}
+
+	useConfigFile(t, "")
+	t.Setenv("MPESA_PROFILE", "")
+	if got := ResolveProfile(""); got != DefaultProfile {
+		t.Errorf("expected '%s', got '%s'", DefaultProfile, got)
+	}
+}
+
+// TestProfileFileManagement tests adding, selecting and removing profiles in the config file
+func TestProfileFileManagement(t *testing.T) {
+	path := useConfigFile(t, "")
+	t.Setenv("MPESA_PROFILE", "")
+
+	err := SaveProfile("paybill", &Config{Environment: "production", BusinessShortcode: "123456", SecurityCredential: "cred"})
+	if err != nil {
+		t.Fatalf("failed to save profile: %v", err)
+	}
+	if err := SaveProfile("till", &Config{Environment: "sandbox", BusinessShortcode: "654321"}); err != nil {
+		t.Fatalf("failed to save profile: %v", err)
+	}
+
+	names, err := ListProfiles()
+	if err != nil {
+		t.Fatalf("failed to list profiles: %v", err)
+	}
+	if expected := []string{DefaultProfile, "paybill", "till"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected profiles %v, got %v", expected, names)
+	}
+
+	if err := SetDefaultProfile("paybill"); err != nil {
+		t.Fatalf("failed to set default profile: %v", err)
+	}
+	if got := ResolveProfile(""); got != "paybill" {
+		t.Errorf("expected default profile 'paybill', got '%s'", got)
+	}
+
+	if err := RemoveProfile("paybill"); err != nil {
+		t.Fatalf("failed to remove profile: %v", err)
+	}
+	if got := ResolveProfile(""); got != DefaultProfile {
+		t.Errorf("expected default profile to be reset, got '%s'", got)
+	}
+	if exists, _ := ProfileExists("paybill"); exists {
+		t.Error("expected removed profile to be gone")
+	}
+
+	content, err := os.ReadFile(path) // #nosec G304 - path is controlled in test
+	if err != nil {
+		t.Fatalf("failed to read config file: %v", err)
+	}
+	if strings.Contains(string(content), "initiator") {
+		t.Errorf("expected defaults not to be written to the config file, got:\n%s", content)
+	}
+
+	if err := SaveProfile("Bad Name", &Config{Environment: "sandbox"}); err == nil {
+		t.Error("expected error for invalid profile name")
+	}
+}
+
+// TestProfileCredentials tests that profiles keep separate credentials in the keychain
+func TestProfileCredentials(t *testing.T) {
+	keyring.MockInit()
+
+	if err := SetCredentials("default-key", "default-secret"); err != nil {
+		t.Fatalf("failed to set credentials: %v", err)
+	}
+	if err := SetProfileCredentials("paybill", "paybill-key", "paybill-secret"); err != nil {
+		t.Fatalf("failed to set profile credentials: %v", err)
+	}
+
+	key, secret, err := GetProfileCredentials("paybill")
+	if err != nil || key != "paybill-key" || secret != "paybill-secret" {
+		t.Errorf("unexpected paybill credentials %q %q %v", key, secret, err)
+	}
+
+	// The default profile keeps the accounts used before profiles existed
+	key, err = keyring.Get(serviceName, "consumer_key")
+	if err != nil || key != "default-key" {
+		t.Errorf("expected default credentials under legacy account, got %q %v", key, err)
+	}
+
+	if err := DeleteProfileCredentials("paybill"); err != nil {
+		t.Fatalf("failed to delete profile credentials: %v", err)
+	}
+	_, _, err = GetProfileCredentials("paybill")
+	if err == nil || !strings.Contains(err.Error(), "mpesa-cli login --profile paybill") {
+		t.Errorf("expected login hint for missing credentials, got %v", err)
+	}
+
+	if _, _, err := GetCredentials(); err != nil {
+		t.Errorf("expected default credentials to remain, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package mpesa
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestPullTransactionsPaging tests following the offset until an empty page
+func TestPullTransactionsPaging(t *testing.T) {
+	const total = 5
+	const pageSize = 2
+	var offsets []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pulltransactions/v1/query" {
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+
+		var req pullQueryRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		offsets = append(offsets, req.OffSetValue)
+
+		if req.StartDate != "2024-07-01 00:00:00" || req.EndDate != "2024-07-02 00:00:00" {
+			t.Errorf("unexpected period %s to %s", req.StartDate, req.EndDate)
+		}
+
+		offset, _ := strconv.Atoi(req.OffSetValue)
+		var page []PullTransaction
+		for i := offset; i < total && i < offset+pageSize; i++ {
+			page = append(page, PullTransaction{TransactionID: fmt.Sprintf("TRX%d", i), Amount: "100"})
+		}
+
+		// The API nests the transactions in an extra array
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"ResponseRefID":   "e7b0-4c1c",
+			"ResponseCode":    "1000",
+			"ResponseMessage": "Success",
+			"Response":        [][]PullTransaction{page},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(GetDefaultConfig(), WithBaseURL(server.URL), WithTokenSource(StaticToken("token")))
+
+	from := time.Date(2024, 7, 1, 0, 0, 0, 0, EAT)
+	var received []PullTransaction
+	err := client.PullTransactions(from, from.Add(24*time.Hour), func(page []PullTransaction) error {
+		received = append(received, page...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(received) != total || received[4].TransactionID != "TRX4" {
+		t.Errorf("expected %d transactions, got %+v", total, received)
+	}
+	expectedOffsets := []string{"0", "2", "4", "5"}
+	if fmt.Sprint(offsets) != fmt.Sprint(expectedOffsets) {
+		t.Errorf("expected offsets %v, got %v", expectedOffsets, offsets)
+	}
+}
+
+// TestPullTransactionsRepeatedPage tests that paging stops if the offset is ignored
+func TestPullTransactionsRepeatedPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ResponseCode":"1000","Response":[{"transactionId":"TRX0"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(GetDefaultConfig(), WithBaseURL(server.URL), WithTokenSource(StaticToken("token")))
+
+	from := time.Now().Add(-time.Hour)
+	err := client.PullTransactions(from, time.Now(), func([]PullTransaction) error { return nil })
+	if err == nil {
+		t.Error("expected error for a repeated page")
+	}
+}
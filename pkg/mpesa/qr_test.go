@@ -0,0 +1,126 @@
+package mpesa
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// testQRModules returns a 21x21 module grid with the three finder patterns of a QR code
+func testQRModules() [][]bool {
+	const size = 21
+	modules := make([][]bool, size)
+	for row := range modules {
+		modules[row] = make([]bool, size)
+		for col := range modules[row] {
+			modules[row][col] = (row*7+col*3)%5 == 0
+		}
+	}
+
+	finder := func(top, left int) {
+		for r := -1; r <= 7; r++ {
+			for c := -1; c <= 7; c++ {
+				row, col := top+r, left+c
+				if row < 0 || col < 0 || row >= size || col >= size {
+					continue
+				}
+				ring := max(abs(r-3), abs(c-3))
+				modules[row][col] = ring != 2 && ring != 4
+			}
+		}
+	}
+	finder(0, 0)
+	finder(0, size-7)
+	finder(size-7, 0)
+
+	return modules
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// testQRPNG draws modules as a PNG with the given module size and a 4 module quiet zone
+func testQRPNG(t *testing.T, modules [][]bool, scale int) []byte {
+	t.Helper()
+
+	dimension := (len(modules) + 8) * scale
+	img := image.NewGray(image.Rect(0, 0, dimension, dimension))
+	for y := 0; y < dimension; y++ {
+		for x := 0; x < dimension; x++ {
+			row, col := y/scale-4, x/scale-4
+			dark := row >= 0 && col >= 0 && row < len(modules) && col < len(modules) && modules[row][col]
+			if dark {
+				img.SetGray(x, y, color.Gray{Y: 0})
+			} else {
+				img.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// TestGenerateQR tests the Dynamic QR request and decoding the returned image
+func TestGenerateQR(t *testing.T) {
+	pngData := testQRPNG(t, testQRModules(), 2)
+	var received qrRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mpesa/qrcode/v1/generate" {
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(qrResponse{
+			ResponseCode:        "AG_20191219_000043fdf61864fe9ff5",
+			RequestID:           "16738-27456357-1",
+			ResponseDescription: "QR Code Successfully Generated.",
+			QRCode:              base64.StdEncoding.EncodeToString(pngData),
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(GetDefaultConfig(), WithBaseURL(server.URL), WithTokenSource(StaticToken("token")))
+
+	result, err := client.GenerateQR(QROptions{MerchantName: "TEST SUPERMARKET", RefNo: "Invoice Test", Amount: 1, TrxCode: QRBuyGoods})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if received.CPI != "600986" || received.TrxCode != QRBuyGoods || received.Size != "300" {
+		t.Errorf("unexpected request %+v", received)
+	}
+
+	decoded, err := result.PNG()
+	if err != nil || !bytes.Equal(decoded, pngData) {
+		t.Errorf("expected decoded PNG, got error %v", err)
+	}
+	if _, err := (&qrResponse{QRCode: base64.StdEncoding.EncodeToString([]byte("not a png"))}).PNG(); err == nil {
+		t.Error("expected error for a QR code that is not a PNG")
+	}
+
+	_, err = client.GenerateQR(QROptions{MerchantName: "TEST", RefNo: "ref", Amount: 1, TrxCode: "XX"})
+	if err == nil || !strings.Contains(err.Error(), "transaction code") {
+		t.Errorf("expected invalid transaction code error, got %v", err)
+	}
+
+	_, err = client.GenerateQR(QROptions{MerchantName: "TEST", RefNo: "ref", Amount: 1, TrxCode: QRWithdrawAgent})
+	if err == nil || !strings.Contains(err.Error(), "CPI") {
+		t.Errorf("expected missing CPI error, got %v", err)
+	}
+}
@@ -0,0 +1,237 @@
+package mpesa
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Frequencies of M-Pesa Ratiba standing orders
+const (
+	FrequencyOneOff     = 1
+	FrequencyDaily      = 2
+	FrequencyWeekly     = 3
+	FrequencyMonthly    = 4
+	FrequencyBiMonthly  = 5
+	FrequencyQuarterly  = 6
+	FrequencyHalfYearly = 7
+	FrequencyYearly     = 8
+)
+
+// Transaction types of standing orders
+const (
+	// StandingOrderPayBill pays into the business shortcode as a Paybill
+	StandingOrderPayBill = "Standing Order Customer Pay Bill"
+
+	// StandingOrderPayMerchant pays into the business shortcode as a Till
+	StandingOrderPayMerchant = "Standing Order Customer Pay Merchant"
+)
+
+const (
+	standingOrderPath = "/standingorder/v1/createStandingOrderExternal"
+
+	// standingOrderDateLayout is the date format of standing order start and end dates
+	standingOrderDateLayout = "20060102"
+)
+
+// StandingOrderOptions describes a recurring payment from a customer to the business shortcode.
+type StandingOrderOptions struct {
+	// Name uniquely identifies the standing order for the customer
+	Name string
+
+	// PhoneNumber is the customer's phone number, in any form accepted by NormalizePhoneNumber
+	PhoneNumber string
+
+	// Amount is the amount of each payment in whole shillings
+	Amount int
+
+	// Frequency is one of the Frequency constants, FrequencyOneOff to FrequencyYearly
+	Frequency int
+
+	// StartDate is the day of the first payment; it must not be in the past
+	StartDate time.Time
+
+	// EndDate is the day after which no more payments are made
+	EndDate time.Time
+
+	// TransactionType is StandingOrderPayBill (the default) or StandingOrderPayMerchant
+	TransactionType string
+
+	// AccountReference identifies the payments to the customer (up to 12 characters)
+	AccountReference string
+
+	// TransactionDesc is a short description of the payments (up to 13 characters)
+	TransactionDesc string
+
+	// CallbackURL receives the result; it defaults to the configured CallbackURL
+	CallbackURL string
+}
+
+// standingOrderRequest represents the JSON payload sent to the M-Pesa Ratiba API.
+type standingOrderRequest struct {
+	// StandingOrderName uniquely identifies the standing order for the customer
+	StandingOrderName string `json:"StandingOrderName"`
+
+	// StartDate is the day of the first payment as YYYYMMDD
+	StartDate string `json:"StartDate"`
+
+	// EndDate is the last day of the standing order as YYYYMMDD
+	EndDate string `json:"EndDate"`
+
+	// BusinessShortCode is the organization's shortcode receiving the payments
+	BusinessShortCode string `json:"BusinessShortCode"`
+
+	// Password is base64(BusinessShortCode + passkey + Timestamp)
+	Password string `json:"Password"`
+
+	// Timestamp is the time of the request in the format YYYYMMDDHHmmss (EAT)
+	Timestamp string `json:"Timestamp"`
+
+	// TransactionType is StandingOrderPayBill or StandingOrderPayMerchant
+	TransactionType string `json:"TransactionType"`
+
+	// ReceiverPartyIdentifierType is the identifier type of the business shortcode
+	ReceiverPartyIdentifierType string `json:"ReceiverPartyIdentifierType"`
+
+	// Amount is the amount of each payment
+	Amount string `json:"Amount"`
+
+	// PartyA is the phone number paying
+	PartyA string `json:"PartyA"`
+
+	// CallBackURL receives the result of the request
+	CallBackURL string `json:"CallBackURL"`
+
+	// AccountReference identifies the payments to the customer
+	AccountReference string `json:"AccountReference"`
+
+	// TransactionDesc is any additional information about the payments
+	TransactionDesc string `json:"TransactionDesc"`
+
+	// Frequency is the frequency code of the payments
+	Frequency string `json:"Frequency"`
+}
+
+// standingOrderResponse represents the JSON response from the M-Pesa Ratiba API.
+type standingOrderResponse struct {
+	// ResponseHeader describes the outcome of the request
+	ResponseHeader struct {
+		ResponseRefID       string `json:"responseRefID"`
+		ResponseCode        string `json:"responseCode"`
+		ResponseDescription string `json:"responseDescription"`
+		ResultDesc          string `json:"ResultDesc"`
+	} `json:"ResponseHeader"`
+
+	// ResponseBody repeats the outcome of the request
+	ResponseBody struct {
+		ResponseDescription string `json:"responseDescription"`
+		ResponseCode        string `json:"responseCode"`
+	} `json:"ResponseBody"`
+}
+
+// ValidateFrequency checks that frequency is a Ratiba frequency code.
+func ValidateFrequency(frequency int) error {
+	if frequency < FrequencyOneOff || frequency > FrequencyYearly {
+		return fmt.Errorf("frequency must be %d to %d, got %d", FrequencyOneOff, FrequencyYearly, frequency)
+	}
+	return nil
+}
+
+// CreateStandingOrder creates a Ratiba standing order that pays the business shortcode.
+// The request is signed with the client's passkey like an STK Push (see WithPasskey),
+// and the customer is prompted to approve the standing order on their phone.
+func (c *Client) CreateStandingOrder(opts StandingOrderOptions) (*standingOrderResponse, error) {
+	reqBody, err := c.newStandingOrderRequest(opts, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var result standingOrderResponse
+	if err := c.postJSON(standingOrderPath, reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// newStandingOrderRequest validates opts and builds the signed standing order payload.
+func (c *Client) newStandingOrderRequest(opts StandingOrderOptions, now time.Time) (*standingOrderRequest, error) {
+	if c.passkey == "" {
+		return nil, fmt.Errorf("a passkey is required for standing orders")
+	}
+	if c.config.BusinessShortcode == "" {
+		return nil, fmt.Errorf("business_shortcode is required for standing orders")
+	}
+	if opts.Name == "" {
+		return nil, fmt.Errorf("a standing order name is required")
+	}
+
+	phone, err := NormalizePhoneNumber(opts.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Amount < 1 {
+		return nil, fmt.Errorf("amount must be at least 1, got %d", opts.Amount)
+	}
+	if err := ValidateFrequency(opts.Frequency); err != nil {
+		return nil, err
+	}
+
+	today := now.In(EAT).Format(standingOrderDateLayout)
+	startDate := opts.StartDate.In(EAT).Format(standingOrderDateLayout)
+	endDate := opts.EndDate.In(EAT).Format(standingOrderDateLayout)
+	if startDate < today {
+		return nil, fmt.Errorf("the start date %s is in the past", opts.StartDate.Format("2006-01-02"))
+	}
+	if endDate <= startDate {
+		return nil, fmt.Errorf("the end date must be after the start date")
+	}
+
+	if opts.AccountReference == "" || len(opts.AccountReference) > maxAccountReferenceLength {
+		return nil, fmt.Errorf("account reference must be 1 to %d characters", maxAccountReferenceLength)
+	}
+	if opts.TransactionDesc == "" || len(opts.TransactionDesc) > maxTransactionDescLength {
+		return nil, fmt.Errorf("transaction description must be 1 to %d characters", maxTransactionDescLength)
+	}
+
+	transactionType := opts.TransactionType
+	if transactionType == "" {
+		transactionType = StandingOrderPayBill
+	}
+	var receiverType string
+	switch transactionType {
+	case StandingOrderPayBill:
+		receiverType = IdentifierShortcode
+	case StandingOrderPayMerchant:
+		receiverType = IdentifierTill
+	default:
+		return nil, fmt.Errorf("transaction type must be '%s' or '%s', got: %s", StandingOrderPayBill, StandingOrderPayMerchant, transactionType)
+	}
+
+	callbackURL := opts.CallbackURL
+	if callbackURL == "" {
+		callbackURL = c.config.CallbackURL
+	}
+	if callbackURL == "" {
+		return nil, fmt.Errorf("a callback URL is required for standing orders")
+	}
+
+	timestamp := FormatTimestamp(now)
+
+	return &standingOrderRequest{
+		StandingOrderName:           opts.Name,
+		StartDate:                   startDate,
+		EndDate:                     endDate,
+		BusinessShortCode:           c.config.BusinessShortcode,
+		Password:                    GeneratePassword(c.config.BusinessShortcode, c.passkey, timestamp),
+		Timestamp:                   timestamp,
+		TransactionType:             transactionType,
+		ReceiverPartyIdentifierType: receiverType,
+		Amount:                      strconv.Itoa(opts.Amount),
+		PartyA:                      phone,
+		CallBackURL:                 callbackURL,
+		AccountReference:            opts.AccountReference,
+		TransactionDesc:             opts.TransactionDesc,
+		Frequency:                   strconv.Itoa(opts.Frequency),
+	}, nil
+}
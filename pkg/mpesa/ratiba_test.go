@@ -0,0 +1,97 @@
+package mpesa
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testStandingOrder(now time.Time) StandingOrderOptions {
+	return StandingOrderOptions{
+		Name:             "Gym Membership",
+		PhoneNumber:      "0708374149",
+		Amount:           4500,
+		Frequency:        FrequencyMonthly,
+		StartDate:        now,
+		EndDate:          now.AddDate(1, 0, 0),
+		AccountReference: "MEMBER-42",
+		TransactionDesc:  "Membership",
+	}
+}
+
+// TestCreateStandingOrder tests the signed Ratiba request
+func TestCreateStandingOrder(t *testing.T) {
+	var received standingOrderRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/standingorder/v1/createStandingOrderExternal" {
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"ResponseHeader":{"responseRefID":"4dd9b5d9-d738-42ba-9326-2cc99e966000","responseCode":"200","responseDescription":"Request accepted for processing","ResultDesc":"The service request is processed successfully."},"ResponseBody":{"responseDescription":"Request accepted for processing","responseCode":"200"}}`))
+	}))
+	defer server.Close()
+
+	config := GetDefaultConfig()
+	config.BusinessShortcode = "174379"
+	client := NewClient(config, WithBaseURL(server.URL), WithTokenSource(StaticToken("token")), WithPasskey(testPasskey))
+
+	start := time.Now().In(EAT)
+	result, err := client.CreateStandingOrder(testStandingOrder(start))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.ResponseHeader.ResponseCode != "200" {
+		t.Errorf("unexpected response %+v", result)
+	}
+
+	if received.Password != GeneratePassword("174379", testPasskey, received.Timestamp) {
+		t.Error("expected password generated from shortcode, passkey and timestamp")
+	}
+	if received.StartDate != start.Format("20060102") || received.Frequency != "4" || received.Amount != "4500" {
+		t.Errorf("unexpected request %+v", received)
+	}
+	if received.PartyA != "254708374149" || received.ReceiverPartyIdentifierType != IdentifierShortcode {
+		t.Errorf("unexpected parties %+v", received)
+	}
+}
+
+// TestStandingOrderValidation tests the local checks of frequency and dates
+func TestStandingOrderValidation(t *testing.T) {
+	now := time.Date(2024, 9, 5, 10, 0, 0, 0, EAT)
+	client := NewClient(GetDefaultConfig(), WithPasskey(testPasskey))
+
+	tests := []struct {
+		name      string
+		modify    func(*StandingOrderOptions)
+		errorText string
+	}{
+		{name: "frequency too low", modify: func(o *StandingOrderOptions) { o.Frequency = 0 }, errorText: "frequency"},
+		{name: "frequency too high", modify: func(o *StandingOrderOptions) { o.Frequency = 9 }, errorText: "frequency"},
+		{name: "start in past", modify: func(o *StandingOrderOptions) { o.StartDate = now.AddDate(0, 0, -1) }, errorText: "in the past"},
+		{name: "end before start", modify: func(o *StandingOrderOptions) { o.EndDate = now }, errorText: "end date"},
+		{name: "missing name", modify: func(o *StandingOrderOptions) { o.Name = "" }, errorText: "name"},
+		{name: "unknown type", modify: func(o *StandingOrderOptions) { o.TransactionType = "Pay Agent" }, errorText: "transaction type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testStandingOrder(now)
+			tt.modify(&opts)
+
+			_, err := client.newStandingOrderRequest(opts, now)
+			if err == nil || !strings.Contains(err.Error(), tt.errorText) {
+				t.Errorf("expected error containing %q, got %v", tt.errorText, err)
+			}
+		})
+	}
+
+	if _, err := client.newStandingOrderRequest(testStandingOrder(now), now); err != nil {
+		t.Errorf("expected start today to be accepted, got %v", err)
+	}
+}
@@ -0,0 +1,19 @@
+package mpesa
+
+import "github.com/martwebber/mpesa-cli/pkg/mpesa/callbacks"
+
+// Result is the outcome of an asynchronous request (B2C, B2B, account balance,
+// transaction status, reversal, ...) as posted by M-Pesa to the ResultURL.
+// Its parameters can be decoded into typed structs; see package callbacks.
+type Result = callbacks.Result
+
+// KeyValue is a single Key/Value item of a result.
+type KeyValue = callbacks.KeyValue
+
+// KeyValues is a list of result items.
+type KeyValues = callbacks.KeyValues
+
+// ParseResult decodes the JSON body posted by M-Pesa to a ResultURL.
+func ParseResult(body []byte) (*Result, error) {
+	return callbacks.ParseResult(body)
+}
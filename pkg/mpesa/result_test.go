@@ -0,0 +1,39 @@
+package mpesa
+
+import (
+	"testing"
+)
+
+// TestParseResult tests decoding results with single and multiple parameters
+func TestParseResult(t *testing.T) {
+	body := []byte(`{"Result":{"ResultType":0,"ResultCode":0,"ResultDesc":"The service request is processed successfully.",
+		"OriginatorConversationID":"16917-22577599-3","ConversationID":"AG_20200206_00005e091a8ec6b9eac5","TransactionID":"OA90000000",
+		"ResultParameters":{"ResultParameter":[{"Key":"AccountBalance","Value":"Working Account|KES|700000.00|700000.00|0.00|0.00"},{"Key":"BOCompletedTime","Value":20200109125710}]},
+		"ReferenceData":{"ReferenceItem":{"Key":"QueueTimeoutURL","Value":"https://internalsandbox.safaricom.co.ke/mpesa/abresults/v1/submit"}}}}`)
+
+	result, err := ParseResult(body)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !result.Succeeded() || result.ConversationID != "AG_20200206_00005e091a8ec6b9eac5" {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if value, ok := result.Parameter("BOCompletedTime"); !ok || value != "20200109125710" {
+		t.Errorf("expected numeric parameter as text, got %q", value)
+	}
+	if _, ok := result.Parameter("Missing"); ok {
+		t.Error("expected missing parameter not to be found")
+	}
+	if value, ok := result.ReferenceData.ReferenceItem.Get("QueueTimeoutURL"); !ok || value == "" {
+		t.Error("expected single reference item to be decoded")
+	}
+
+	failed, err := ParseResult([]byte(`{"Result":{"ResultType":0,"ResultCode":"2001","ResultDesc":"The initiator information is invalid."}}`))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if failed.Succeeded() || failed.ResultCode.String() != "2001" {
+		t.Errorf("expected failed result, got %+v", failed)
+	}
+}
@@ -0,0 +1,111 @@
+package mpesa
+
+import (
+	"fmt"
+)
+
+const (
+	reversalPath = "/mpesa/reversal/v1/request"
+
+	// IdentifierReversal is the receiver identifier type the Reversal API expects for organizations
+	IdentifierReversal = "11"
+)
+
+// ReversalOptions describes the reversal of a completed transaction.
+type ReversalOptions struct {
+	// TransactionID is the M-Pesa receipt number of the transaction to reverse
+	TransactionID string
+
+	// Amount is the amount of the transaction in whole shillings
+	Amount int
+
+	// Receiver is the shortcode that received the transaction; it defaults to the business shortcode
+	Receiver string
+
+	// Remarks are additional information for the reversal (2 to 100 characters)
+	Remarks string
+
+	// Occasion is any additional information to be associated with the reversal
+	Occasion string
+}
+
+// reversalRequest represents the JSON payload sent to the M-Pesa Reversal API.
+type reversalRequest struct {
+	// Initiator is the name of the user initiating the reversal
+	Initiator string `json:"Initiator"`
+
+	// SecurityCredential is the encrypted credential of the initiator
+	SecurityCredential string `json:"SecurityCredential"`
+
+	// CommandID is always TransactionReversal
+	CommandID string `json:"CommandID"`
+
+	// TransactionID is the M-Pesa receipt number of the transaction to reverse
+	TransactionID string `json:"TransactionID"`
+
+	// Amount is the amount of the transaction
+	Amount int `json:"Amount"`
+
+	// ReceiverParty is the shortcode that received the transaction
+	ReceiverParty string `json:"ReceiverParty"`
+
+	// RecieverIdentifierType is the identifier type of ReceiverParty (sic, as spelled by the API)
+	RecieverIdentifierType string `json:"RecieverIdentifierType"`
+
+	// ResultURL is the path that receives the result of the reversal
+	ResultURL string `json:"ResultURL"`
+
+	// QueueTimeOutURL is the path that stores information of time out transaction
+	QueueTimeOutURL string `json:"QueueTimeOutURL"`
+
+	// Remarks are additional information for the reversal
+	Remarks string `json:"Remarks"`
+
+	// Occasion is any additional information to be associated with the reversal
+	Occasion string `json:"Occasion"`
+}
+
+// ReverseTransaction requests the reversal of a completed transaction using the
+// initiator, security credential and callback URLs from the client's config.
+// The outcome of the reversal is delivered to the ResultURL.
+func (c *Client) ReverseTransaction(opts ReversalOptions) (*AsyncResponse, error) {
+	if err := c.requireInitiator("reversals"); err != nil {
+		return nil, err
+	}
+
+	if opts.TransactionID == "" {
+		return nil, fmt.Errorf("the transaction ID is required")
+	}
+	if opts.Amount < 1 {
+		return nil, fmt.Errorf("amount must be at least 1, got %d", opts.Amount)
+	}
+	if len(opts.Remarks) < minRemarksLength || len(opts.Remarks) > maxRemarksLength {
+		return nil, fmt.Errorf("remarks must be %d to %d characters", minRemarksLength, maxRemarksLength)
+	}
+
+	receiver := opts.Receiver
+	if receiver == "" {
+		receiver = c.config.BusinessShortcode
+	}
+
+	reqBody := reversalRequest{
+		Initiator:              c.config.Initiator,
+		SecurityCredential:     c.config.SecurityCredential,
+		CommandID:              "TransactionReversal",
+		TransactionID:          opts.TransactionID,
+		Amount:                 opts.Amount,
+		ReceiverParty:          receiver,
+		RecieverIdentifierType: IdentifierReversal,
+		ResultURL:              c.config.ResultURL,
+		QueueTimeOutURL:        c.config.QueueTimeOutURL,
+		Remarks:                opts.Remarks,
+		Occasion:               opts.Occasion,
+	}
+
+	var result AsyncResponse
+	if err := c.postJSON(reversalPath, reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
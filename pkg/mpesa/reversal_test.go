@@ -0,0 +1,56 @@
+package mpesa
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestReverseTransaction tests the Reversal request built from the config
+func TestReverseTransaction(t *testing.T) {
+	var received reversalRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mpesa/reversal/v1/request" {
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"ConversationID":"AG_20240706_5010","OriginatorConversationID":"f1e2-4b95-a71d","ResponseCode":"0","ResponseDescription":"Accept the service request successfully."}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(testConfig(), WithBaseURL(server.URL), WithTokenSource(StaticToken("token")))
+
+	result, err := client.ReverseTransaction(ReversalOptions{TransactionID: "OEI2AK4Q16", Amount: 100, Remarks: "Wrong payment"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.ConversationID != "AG_20240706_5010" {
+		t.Errorf("unexpected response %+v", result)
+	}
+
+	expected := reversalRequest{
+		Initiator:              "testapi",
+		SecurityCredential:     "EncryptedCredential",
+		CommandID:              "TransactionReversal",
+		TransactionID:          "OEI2AK4Q16",
+		Amount:                 100,
+		ReceiverParty:          "600986",
+		RecieverIdentifierType: IdentifierReversal,
+		ResultURL:              "https://domain.com/result",
+		QueueTimeOutURL:        "https://domain.com/timeout",
+		Remarks:                "Wrong payment",
+	}
+	if received != expected {
+		t.Errorf("expected request %+v, got %+v", expected, received)
+	}
+
+	_, err = client.ReverseTransaction(ReversalOptions{Amount: 100, Remarks: "Wrong payment"})
+	if err == nil || !strings.Contains(err.Error(), "transaction ID") {
+		t.Errorf("expected missing transaction ID error, got %v", err)
+	}
+}
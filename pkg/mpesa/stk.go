@@ -0,0 +1,329 @@
+package mpesa
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Transaction types of Lipa Na M-Pesa Online (STK Push) requests
+const (
+	// CustomerPayBillOnline charges the customer into a Paybill number
+	CustomerPayBillOnline = "CustomerPayBillOnline"
+
+	// CustomerBuyGoodsOnline charges the customer into a Till number
+	CustomerBuyGoodsOnline = "CustomerBuyGoodsOnline"
+)
+
+const (
+	stkPushPath  = "/mpesa/stkpush/v1/processrequest"
+	stkQueryPath = "/mpesa/stkpushquery/v1/query"
+
+	// stkPendingErrorCode is the error the STK Push query API answers with
+	// while the customer has not yet responded to the prompt
+	stkPendingErrorCode = "500.001.1001"
+
+	// Field limits enforced by the STK Push API
+	maxAccountReferenceLength = 12
+	maxTransactionDescLength  = 13
+)
+
+// Result codes of STK Push transactions
+const (
+	// STKResultSuccess means the customer completed the payment
+	STKResultSuccess = "0"
+
+	// STKResultCancelled means the customer cancelled the prompt
+	STKResultCancelled = "1032"
+
+	// STKResultTimeout means the customer did not respond to the prompt in time
+	STKResultTimeout = "1037"
+)
+
+// ErrSTKPending is returned while an STK Push transaction is still waiting for the customer.
+var ErrSTKPending = errors.New("the transaction is still being processed")
+
+// Polling intervals of WaitForSTKResult (variables so tests can shorten them)
+var (
+	stkPollInterval    = 2 * time.Second
+	stkMaxPollInterval = 15 * time.Second
+)
+
+// EAT is East Africa Time, the time zone Daraja expects request timestamps and dates in
+var EAT = time.FixedZone("EAT", 3*60*60)
+
+// STKPushOptions describes a Lipa Na M-Pesa Online payment request.
+type STKPushOptions struct {
+	// PhoneNumber is the customer's phone number, in any form accepted by NormalizePhoneNumber
+	PhoneNumber string
+
+	// Amount is the amount to charge in whole shillings
+	Amount int
+
+	// AccountReference identifies the payment to the customer (up to 12 characters)
+	AccountReference string
+
+	// TransactionDesc is a short description of the payment (up to 13 characters)
+	TransactionDesc string
+
+	// TransactionType is CustomerPayBillOnline (the default) or CustomerBuyGoodsOnline
+	TransactionType string
+
+	// PartyB receives the funds; it defaults to the business shortcode and must be
+	// the Till number for CustomerBuyGoodsOnline
+	PartyB string
+
+	// CallbackURL receives the result; it defaults to the configured CallbackURL
+	CallbackURL string
+}
+
+// stkPushRequest represents the JSON payload sent to the M-Pesa STK Push API.
+type stkPushRequest struct {
+	// BusinessShortCode is the organization's shortcode (Paybill or Buygoods store number)
+	BusinessShortCode string `json:"BusinessShortCode"`
+
+	// Password is base64(BusinessShortCode + passkey + Timestamp)
+	Password string `json:"Password"`
+
+	// Timestamp is the time of the request in the format YYYYMMDDHHmmss (EAT)
+	Timestamp string `json:"Timestamp"`
+
+	// TransactionType is CustomerPayBillOnline or CustomerBuyGoodsOnline
+	TransactionType string `json:"TransactionType"`
+
+	// Amount is the amount to be transacted
+	Amount int `json:"Amount"`
+
+	// PartyA is the phone number sending the money
+	PartyA string `json:"PartyA"`
+
+	// PartyB is the organization receiving the funds
+	PartyB string `json:"PartyB"`
+
+	// PhoneNumber is the phone number that receives the STK prompt
+	PhoneNumber string `json:"PhoneNumber"`
+
+	// CallBackURL receives the result of the request
+	CallBackURL string `json:"CallBackURL"`
+
+	// AccountReference identifies the transaction to the customer
+	AccountReference string `json:"AccountReference"`
+
+	// TransactionDesc is any additional information about the transaction
+	TransactionDesc string `json:"TransactionDesc"`
+}
+
+// stkPushResponse represents the JSON response from the M-Pesa STK Push API.
+type stkPushResponse struct {
+	// MerchantRequestID is the global unique identifier of the submitted payment request
+	MerchantRequestID string `json:"MerchantRequestID"`
+
+	// CheckoutRequestID is the global unique identifier of the processed checkout transaction
+	CheckoutRequestID string `json:"CheckoutRequestID"`
+
+	// ResponseCode indicates the status of the request (0 for success)
+	ResponseCode string `json:"ResponseCode"`
+
+	// ResponseDescription provides a human-readable description of the response
+	ResponseDescription string `json:"ResponseDescription"`
+
+	// CustomerMessage is the message shown to the customer
+	CustomerMessage string `json:"CustomerMessage"`
+}
+
+// stkQueryRequest represents the JSON payload sent to the M-Pesa STK Push query API.
+type stkQueryRequest struct {
+	// BusinessShortCode is the organization's shortcode used for the STK Push
+	BusinessShortCode string `json:"BusinessShortCode"`
+
+	// Password is base64(BusinessShortCode + passkey + Timestamp)
+	Password string `json:"Password"`
+
+	// Timestamp is the time of the request in the format YYYYMMDDHHmmss (EAT)
+	Timestamp string `json:"Timestamp"`
+
+	// CheckoutRequestID identifies the STK Push transaction being queried
+	CheckoutRequestID string `json:"CheckoutRequestID"`
+}
+
+// stkQueryResponse represents the JSON response from the M-Pesa STK Push query API.
+type stkQueryResponse struct {
+	// ResponseCode indicates the status of the query request (0 for success)
+	ResponseCode string `json:"ResponseCode"`
+
+	// ResponseDescription provides a human-readable description of the response
+	ResponseDescription string `json:"ResponseDescription"`
+
+	// MerchantRequestID is the global unique identifier of the submitted payment request
+	MerchantRequestID string `json:"MerchantRequestID"`
+
+	// CheckoutRequestID is the global unique identifier of the processed checkout transaction
+	CheckoutRequestID string `json:"CheckoutRequestID"`
+
+	// ResultCode is the outcome of the transaction, e.g. STKResultSuccess or STKResultCancelled
+	ResultCode string `json:"ResultCode"`
+
+	// ResultDesc provides a human-readable description of the outcome
+	ResultDesc string `json:"ResultDesc"`
+}
+
+// WithPasskey sets the Lipa Na M-Pesa Online passkey used to sign STK Push requests.
+func WithPasskey(passkey string) Option {
+	return func(c *Client) {
+		c.passkey = passkey
+	}
+}
+
+// FormatTimestamp formats t in East Africa Time as YYYYMMDDHHmmss, the timestamp format of the M-Pesa API.
+func FormatTimestamp(t time.Time) string {
+	return t.In(EAT).Format("20060102150405")
+}
+
+// GeneratePassword returns the password of a Lipa Na M-Pesa Online request:
+// the base64 encoding of shortcode, passkey and timestamp concatenated.
+func GeneratePassword(shortcode, passkey, timestamp string) string {
+	return base64.StdEncoding.EncodeToString([]byte(shortcode + passkey + timestamp))
+}
+
+// STKPush sends a Lipa Na M-Pesa Online payment prompt to the customer's phone.
+// The request is signed with the client's passkey (see WithPasskey).
+func (c *Client) STKPush(opts STKPushOptions) (*stkPushResponse, error) {
+	reqBody, err := c.newSTKPushRequest(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var result stkPushResponse
+	if err := c.postJSON(stkPushPath, reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// newSTKPushRequest validates opts and builds the signed STK Push payload.
+func (c *Client) newSTKPushRequest(opts STKPushOptions) (*stkPushRequest, error) {
+	if c.passkey == "" {
+		return nil, fmt.Errorf("a passkey is required for STK Push requests")
+	}
+	if c.config.BusinessShortcode == "" {
+		return nil, fmt.Errorf("business_shortcode is required for STK Push requests")
+	}
+
+	phone, err := NormalizePhoneNumber(opts.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Amount < 1 {
+		return nil, fmt.Errorf("amount must be at least 1, got %d", opts.Amount)
+	}
+	if opts.AccountReference == "" || len(opts.AccountReference) > maxAccountReferenceLength {
+		return nil, fmt.Errorf("account reference must be 1 to %d characters", maxAccountReferenceLength)
+	}
+	if opts.TransactionDesc == "" || len(opts.TransactionDesc) > maxTransactionDescLength {
+		return nil, fmt.Errorf("transaction description must be 1 to %d characters", maxTransactionDescLength)
+	}
+
+	transactionType := opts.TransactionType
+	if transactionType == "" {
+		transactionType = CustomerPayBillOnline
+	}
+
+	partyB := opts.PartyB
+	switch transactionType {
+	case CustomerPayBillOnline:
+		if partyB == "" {
+			partyB = c.config.BusinessShortcode
+		}
+	case CustomerBuyGoodsOnline:
+		if partyB == "" {
+			return nil, fmt.Errorf("the till number is required for %s", CustomerBuyGoodsOnline)
+		}
+	default:
+		return nil, fmt.Errorf("transaction type must be %s or %s, got: %s", CustomerPayBillOnline, CustomerBuyGoodsOnline, transactionType)
+	}
+
+	callbackURL := opts.CallbackURL
+	if callbackURL == "" {
+		callbackURL = c.config.CallbackURL
+	}
+	if callbackURL == "" {
+		return nil, fmt.Errorf("a callback URL is required for STK Push requests")
+	}
+
+	timestamp := FormatTimestamp(time.Now())
+
+	return &stkPushRequest{
+		BusinessShortCode: c.config.BusinessShortcode,
+		Password:          GeneratePassword(c.config.BusinessShortcode, c.passkey, timestamp),
+		Timestamp:         timestamp,
+		TransactionType:   transactionType,
+		Amount:            opts.Amount,
+		PartyA:            phone,
+		PartyB:            partyB,
+		PhoneNumber:       phone,
+		CallBackURL:       callbackURL,
+		AccountReference:  opts.AccountReference,
+		TransactionDesc:   opts.TransactionDesc,
+	}, nil
+}
+
+// STKQuery returns the outcome of an STK Push transaction.
+// It returns ErrSTKPending while the customer has not yet responded to the prompt.
+func (c *Client) STKQuery(checkoutRequestID string) (*stkQueryResponse, error) {
+	if c.passkey == "" {
+		return nil, fmt.Errorf("a passkey is required for STK Push requests")
+	}
+	if checkoutRequestID == "" {
+		return nil, fmt.Errorf("checkout request ID is required")
+	}
+
+	timestamp := FormatTimestamp(time.Now())
+	reqBody := stkQueryRequest{
+		BusinessShortCode: c.config.BusinessShortcode,
+		Password:          GeneratePassword(c.config.BusinessShortcode, c.passkey, timestamp),
+		Timestamp:         timestamp,
+		CheckoutRequestID: checkoutRequestID,
+	}
+
+	var result stkQueryResponse
+	if err := c.postJSON(stkQueryPath, reqBody, &result); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode == stkPendingErrorCode {
+			return nil, ErrSTKPending
+		}
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// WaitForSTKResult polls STKQuery with increasing intervals until the transaction
+// reaches a final state. It returns ErrSTKPending if timeout elapses first.
+func (c *Client) WaitForSTKResult(checkoutRequestID string, timeout time.Duration) (*stkQueryResponse, error) {
+	deadline := time.Now().Add(timeout)
+	interval := stkPollInterval
+
+	for {
+		result, err := c.STKQuery(checkoutRequestID)
+		if !errors.Is(err, ErrSTKPending) {
+			return result, err
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, ErrSTKPending
+		}
+		if interval > remaining {
+			interval = remaining
+		}
+		time.Sleep(interval)
+
+		interval = interval * 3 / 2
+		if interval > stkMaxPollInterval {
+			interval = stkMaxPollInterval
+		}
+	}
+}
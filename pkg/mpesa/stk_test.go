@@ -0,0 +1,203 @@
+package mpesa
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testPasskey = "bfb279f9aa9bdbcf158e97dd71a467cd2e0c893059b10f78e6b72ada1ed2c919" // #nosec G101 - public sandbox passkey
+
+// TestGeneratePassword tests the STK Push password derivation
+func TestGeneratePassword(t *testing.T) {
+	// base64("174379" + passkey + "20160216165627") from the Daraja documentation
+	expected := "MTc0Mzc5YmZiMjc5ZjlhYTliZGJjZjE1OGU5N2RkNzFhNDY3Y2QyZTBjODkzMDU5YjEwZjc4ZTZiNzJhZGExZWQyYzkxOTIwMTYwMjE2MTY1NjI3"
+
+	if got := GeneratePassword("174379", testPasskey, "20160216165627"); got != expected {
+		t.Errorf("expected password '%s', got '%s'", expected, got)
+	}
+}
+
+// TestFormatTimestamp tests that timestamps are formatted in East Africa Time
+func TestFormatTimestamp(t *testing.T) {
+	utc := time.Date(2024, 1, 31, 22, 30, 5, 0, time.UTC)
+
+	if got := FormatTimestamp(utc); got != "20240201013005" {
+		t.Errorf("expected '20240201013005', got '%s'", got)
+	}
+}
+
+// TestSTKPushSuccess tests the payload and response of an STK Push request
+func TestSTKPushSuccess(t *testing.T) {
+	var received stkPushRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mpesa/stkpush/v1/processrequest" {
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(stkPushResponse{
+			MerchantRequestID:   "29115-34620561-1",
+			CheckoutRequestID:   "ws_CO_191220191020363925",
+			ResponseCode:        "0",
+			ResponseDescription: "Success. Request accepted for processing",
+			CustomerMessage:     "Success. Request accepted for processing",
+		})
+	}))
+	defer server.Close()
+
+	config := GetDefaultConfig()
+	config.BusinessShortcode = "174379"
+	client := NewClient(config, WithBaseURL(server.URL), WithTokenSource(StaticToken("token")), WithPasskey(testPasskey))
+
+	result, err := client.STKPush(STKPushOptions{
+		PhoneNumber:      "0712345678",
+		Amount:           10,
+		AccountReference: "INV001",
+		TransactionDesc:  "Payment",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if result.CheckoutRequestID != "ws_CO_191220191020363925" || result.MerchantRequestID != "29115-34620561-1" {
+		t.Errorf("unexpected response %+v", result)
+	}
+
+	if received.TransactionType != CustomerPayBillOnline {
+		t.Errorf("expected default transaction type %s, got %s", CustomerPayBillOnline, received.TransactionType)
+	}
+	if received.PartyA != "254712345678" || received.PhoneNumber != "254712345678" {
+		t.Errorf("expected normalized phone number, got PartyA=%s PhoneNumber=%s", received.PartyA, received.PhoneNumber)
+	}
+	if received.PartyB != "174379" || received.BusinessShortCode != "174379" {
+		t.Errorf("expected shortcode as PartyB, got %+v", received)
+	}
+	if received.CallBackURL != config.CallbackURL {
+		t.Errorf("expected configured callback URL, got %s", received.CallBackURL)
+	}
+	if received.Password != GeneratePassword("174379", testPasskey, received.Timestamp) {
+		t.Error("password does not match shortcode, passkey and timestamp")
+	}
+	if _, err := time.ParseInLocation("20060102150405", received.Timestamp, EAT); err != nil {
+		t.Errorf("invalid timestamp %s: %v", received.Timestamp, err)
+	}
+}
+
+// TestSTKPushValidation tests that invalid requests are rejected before sending
+func TestSTKPushValidation(t *testing.T) {
+	valid := STKPushOptions{PhoneNumber: "0712345678", Amount: 1, AccountReference: "REF", TransactionDesc: "Desc"}
+
+	tests := []struct {
+		name      string
+		passkey   string
+		modify    func(o *STKPushOptions)
+		errorText string
+	}{
+		{name: "missing passkey", passkey: "", modify: func(o *STKPushOptions) {}, errorText: "passkey"},
+		{name: "invalid phone", passkey: testPasskey, modify: func(o *STKPushOptions) { o.PhoneNumber = "123" }, errorText: "invalid phone number"},
+		{name: "zero amount", passkey: testPasskey, modify: func(o *STKPushOptions) { o.Amount = 0 }, errorText: "amount"},
+		{name: "long reference", passkey: testPasskey, modify: func(o *STKPushOptions) { o.AccountReference = "ABCDEFGHIJKLM" }, errorText: "account reference"},
+		{name: "long description", passkey: testPasskey, modify: func(o *STKPushOptions) { o.TransactionDesc = "ABCDEFGHIJKLMN" }, errorText: "transaction description"},
+		{name: "buy goods without till", passkey: testPasskey, modify: func(o *STKPushOptions) { o.TransactionType = CustomerBuyGoodsOnline }, errorText: "till number"},
+		{name: "unknown type", passkey: testPasskey, modify: func(o *STKPushOptions) { o.TransactionType = "Other" }, errorText: "transaction type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := valid
+			tt.modify(&opts)
+
+			client := NewClient(GetDefaultConfig(), WithBaseURL("http://127.0.0.1:0"), WithTokenSource(StaticToken("token")), WithPasskey(tt.passkey))
+			_, err := client.STKPush(opts)
+			if err == nil || !strings.Contains(err.Error(), tt.errorText) {
+				t.Errorf("expected error containing '%s', got %v", tt.errorText, err)
+			}
+		})
+	}
+}
+
+// stkQueryServer answers STK Push queries with the pending error until pendingCount queries have been made
+func stkQueryServer(t *testing.T, pendingCount int, resultCode string) (*httptest.Server, *int) {
+	t.Helper()
+
+	queries := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mpesa/stkpushquery/v1/query" {
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+
+		var received stkQueryRequest
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		if received.CheckoutRequestID != "ws_CO_123" {
+			t.Errorf("unexpected checkout request ID %s", received.CheckoutRequestID)
+		}
+
+		queries++
+		if queries <= pendingCount {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"requestId":"1","errorCode":"500.001.1001","errorMessage":"The transaction is being processed"}`))
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(stkQueryResponse{
+			ResponseCode:      "0",
+			CheckoutRequestID: received.CheckoutRequestID,
+			ResultCode:        resultCode,
+			ResultDesc:        "Request cancelled by user",
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &queries
+}
+
+// TestSTKQuery tests final and pending STK Push query results
+func TestSTKQuery(t *testing.T) {
+	server, _ := stkQueryServer(t, 1, STKResultCancelled)
+	client := NewClient(GetDefaultConfig(), WithBaseURL(server.URL), WithTokenSource(StaticToken("token")), WithPasskey(testPasskey))
+
+	if _, err := client.STKQuery("ws_CO_123"); err != ErrSTKPending {
+		t.Fatalf("expected ErrSTKPending, got %v", err)
+	}
+
+	result, err := client.STKQuery("ws_CO_123")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.ResultCode != STKResultCancelled {
+		t.Errorf("expected result code %s, got %s", STKResultCancelled, result.ResultCode)
+	}
+}
+
+// TestWaitForSTKResult tests polling until a final result or the timeout
+func TestWaitForSTKResult(t *testing.T) {
+	oldInterval, oldMax := stkPollInterval, stkMaxPollInterval
+	stkPollInterval, stkMaxPollInterval = time.Millisecond, 2*time.Millisecond
+	defer func() { stkPollInterval, stkMaxPollInterval = oldInterval, oldMax }()
+
+	server, queries := stkQueryServer(t, 3, STKResultSuccess)
+	client := NewClient(GetDefaultConfig(), WithBaseURL(server.URL), WithTokenSource(StaticToken("token")), WithPasskey(testPasskey))
+
+	result, err := client.WaitForSTKResult("ws_CO_123", time.Second)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.ResultCode != STKResultSuccess || *queries != 4 {
+		t.Errorf("expected success after 4 queries, got code %s after %d", result.ResultCode, *queries)
+	}
+
+	server, _ = stkQueryServer(t, 1000, STKResultSuccess)
+	client = NewClient(GetDefaultConfig(), WithBaseURL(server.URL), WithTokenSource(StaticToken("token")), WithPasskey(testPasskey))
+
+	if _, err := client.WaitForSTKResult("ws_CO_123", 20*time.Millisecond); err != ErrSTKPending {
+		t.Errorf("expected ErrSTKPending after timeout, got %v", err)
+	}
+}
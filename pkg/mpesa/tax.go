@@ -0,0 +1,68 @@
+package mpesa
+
+import (
+	"fmt"
+)
+
+const (
+	// PayTaxToKRA is the command ID of tax remittances
+	PayTaxToKRA = "PayTaxToKRA"
+
+	// KRAShortcode is the shortcode of the Kenya Revenue Authority that receives tax remittances
+	KRAShortcode = "572572"
+
+	taxRemitPath = "/mpesa/b2b/v1/remittax"
+)
+
+// TaxRemitOptions describes a tax payment from the business shortcode to KRA.
+type TaxRemitOptions struct {
+	// Amount is the amount to pay in whole shillings
+	Amount int
+
+	// PRN is the payment registration number generated by KRA for the payment
+	PRN string
+
+	// Remarks are additional information for the payment (2 to 100 characters)
+	Remarks string
+}
+
+// RemitTax pays tax to KRA from the business shortcode using the initiator, security
+// credential and callback URLs from the client's config. The outcome of the payment
+// is delivered to the ResultURL.
+func (c *Client) RemitTax(opts TaxRemitOptions) (*AsyncResponse, error) {
+	if err := c.requireInitiator("tax remittances"); err != nil {
+		return nil, err
+	}
+
+	if opts.PRN == "" {
+		return nil, fmt.Errorf("the payment registration number (PRN) is required")
+	}
+	if opts.Amount < 1 {
+		return nil, fmt.Errorf("amount must be at least 1, got %d", opts.Amount)
+	}
+	if len(opts.Remarks) < minRemarksLength || len(opts.Remarks) > maxRemarksLength {
+		return nil, fmt.Errorf("remarks must be %d to %d characters", minRemarksLength, maxRemarksLength)
+	}
+
+	reqBody := b2bRequest{
+		Initiator:              c.config.Initiator,
+		SecurityCredential:     c.config.SecurityCredential,
+		CommandID:              PayTaxToKRA,
+		SenderIdentifierType:   IdentifierShortcode,
+		RecieverIdentifierType: IdentifierShortcode,
+		Amount:                 opts.Amount,
+		PartyA:                 c.config.BusinessShortcode,
+		PartyB:                 KRAShortcode,
+		AccountReference:       opts.PRN,
+		Remarks:                opts.Remarks,
+		QueueTimeOutURL:        c.config.QueueTimeOutURL,
+		ResultURL:              c.config.ResultURL,
+	}
+
+	var result AsyncResponse
+	if err := c.postJSON(taxRemitPath, reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
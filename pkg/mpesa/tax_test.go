@@ -0,0 +1,47 @@
+package mpesa
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestRemitTax tests that tax is paid to the KRA shortcode with the PRN as account reference
+func TestRemitTax(t *testing.T) {
+	var received b2bRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mpesa/b2b/v1/remittax" {
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"ConversationID":"AG_20240706_6010","OriginatorConversationID":"5118-111210482-1","ResponseCode":"0","ResponseDescription":"Accept the service request successfully."}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(testConfig(), WithBaseURL(server.URL), WithTokenSource(StaticToken("token")))
+
+	result, err := client.RemitTax(TaxRemitOptions{Amount: 2400, PRN: "353353", Remarks: "PAYE July"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.ConversationID != "AG_20240706_6010" {
+		t.Errorf("unexpected response %+v", result)
+	}
+
+	if received.CommandID != PayTaxToKRA || received.PartyB != KRAShortcode || received.AccountReference != "353353" {
+		t.Errorf("unexpected request %+v", received)
+	}
+	if received.ResultURL != "https://domain.com/result" || received.SecurityCredential != "EncryptedCredential" {
+		t.Errorf("expected result URL and security credential from config, got %+v", received)
+	}
+
+	_, err = client.RemitTax(TaxRemitOptions{Amount: 2400, Remarks: "PAYE July"})
+	if err == nil || !strings.Contains(err.Error(), "PRN") {
+		t.Errorf("expected missing PRN error, got %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package mpesa
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cachedToken is a single access token entry in the token cache file
+type cachedToken struct {
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// TokenCache persists access tokens between CLI invocations so that every
+// command does not need a fresh OAuth round trip. Tokens are stored in a JSON
+// file readable only by the current user, keyed by environment and consumer key.
+type TokenCache struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewTokenCache returns a token cache backed by the file at path.
+// The file and its directory are created on the first write.
+func NewTokenCache(path string) *TokenCache {
+	return &TokenCache{path: path}
+}
+
+// DefaultTokenCachePath returns the location of the token cache in the user's cache directory.
+func DefaultTokenCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine cache directory: %w", err)
+	}
+	return filepath.Join(dir, "mpesa-cli", "tokens.json"), nil
+}
+
+// TokenCacheKey returns the cache key for tokens issued to consumerKey in environment.
+// Clients with a custom base URL pass that URL as environment. The consumer key is hashed so that it is not written to the cache file in clear.
+func TokenCacheKey(environment, consumerKey string) string {
+	return environment + ":" + consumerKeyHash(consumerKey)
+}
+
+// consumerKeyHash returns the part of a cache key that identifies consumerKey.
+func consumerKeyHash(consumerKey string) string {
+	sum := sha256.Sum256([]byte(consumerKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+// splitCacheKey returns the environment (or base URL) and consumer key hash of a cache key.
+func splitCacheKey(key string) (string, string) {
+	i := strings.LastIndex(key, ":")
+	if i < 0 {
+		return "", key
+	}
+	return key[:i], key[i+1:]
+}
+
+// Get returns the cached token for key and its expiry time.
+// The boolean is false if there is no unexpired token for key.
+func (c *TokenCache) Get(key string) (string, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tokens, err := c.load()
+	if err != nil {
+		return "", time.Time{}, false
+	}
+
+	token, ok := tokens[key]
+	if !ok || token.AccessToken == "" || !time.Now().Before(token.ExpiresAt) {
+		return "", time.Time{}, false
+	}
+
+	return token.AccessToken, token.ExpiresAt, true
+}
+
+// Put stores accessToken for key until expiresAt.
+func (c *TokenCache) Put(key, accessToken string, expiresAt time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tokens, err := c.load()
+	if err != nil {
+		tokens = map[string]cachedToken{}
+	}
+
+	// Drop expired entries while we are rewriting the file anyway
+	now := time.Now()
+	for k, token := range tokens {
+		if !now.Before(token.ExpiresAt) {
+			delete(tokens, k)
+		}
+	}
+
+	tokens[key] = cachedToken{AccessToken: accessToken, ExpiresAt: expiresAt}
+	return c.save(tokens)
+}
+
+// Delete removes the cached token for key, e.g. after the API rejected it.
+func (c *TokenCache) Delete(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tokens, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := tokens[key]; !ok {
+		return nil
+	}
+
+	delete(tokens, key)
+	return c.save(tokens)
+}
+
+// Expiries returns the expiry times of the unexpired tokens cached for consumerKey,
+// keyed by environment, or by base URL for clients pointed elsewhere.
+func (c *TokenCache) Expiries(consumerKey string) map[string]time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiries := map[string]time.Time{}
+	tokens, err := c.load()
+	if err != nil {
+		return expiries
+	}
+
+	now := time.Now()
+	hash := consumerKeyHash(consumerKey)
+	for key, token := range tokens {
+		environment, keyHash := splitCacheKey(key)
+		if keyHash == hash && token.AccessToken != "" && now.Before(token.ExpiresAt) {
+			expiries[environment] = token.ExpiresAt
+		}
+	}
+	return expiries
+}
+
+// DeleteConsumer removes every token cached for consumerKey, whatever the
+// environment or base URL it was issued for.
+func (c *TokenCache) DeleteConsumer(consumerKey string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tokens, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	hash := consumerKeyHash(consumerKey)
+	deleted := false
+	for key := range tokens {
+		if _, keyHash := splitCacheKey(key); keyHash == hash {
+			delete(tokens, key)
+			deleted = true
+		}
+	}
+	if !deleted {
+		return nil
+	}
+
+	return c.save(tokens)
+}
+
+// load reads all cached tokens. A missing cache file is not an error.
+func (c *TokenCache) load() (map[string]cachedToken, error) {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]cachedToken{}, nil
+		}
+		return nil, fmt.Errorf("failed to read token cache: %w", err)
+	}
+
+	tokens := map[string]cachedToken{}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to parse token cache: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// save atomically replaces the cache file with tokens.
+func (c *TokenCache) save(tokens map[string]cachedToken) error {
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode token cache: %w", err)
+	}
+
+	if err := writeFileAtomic(c.path, data); err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+
+	return nil
+}
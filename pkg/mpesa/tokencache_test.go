@@ -0,0 +1,109 @@
+package mpesa
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestTokenCachePutGet tests storing and retrieving tokens across cache instances
+func TestTokenCachePutGet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mpesa-cli", "tokens.json")
+	key := TokenCacheKey("sandbox", "consumer-key")
+	expiresAt := time.Now().Add(time.Hour).Round(time.Second)
+
+	if err := NewTokenCache(path).Put(key, "cached-token", expiresAt); err != nil {
+		t.Fatalf("failed to put token: %v", err)
+	}
+
+	token, gotExpiry, ok := NewTokenCache(path).Get(key)
+	if !ok {
+		t.Fatal("expected cached token, got none")
+	}
+	if token != "cached-token" {
+		t.Errorf("expected token 'cached-token', got '%s'", token)
+	}
+	if !gotExpiry.Equal(expiresAt) {
+		t.Errorf("expected expiry %v, got %v", expiresAt, gotExpiry)
+	}
+
+	if _, _, ok := NewTokenCache(path).Get(TokenCacheKey("production", "consumer-key")); ok {
+		t.Error("expected no token for a different environment")
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("failed to stat cache file: %v", err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("expected cache file mode 0600, got %o", info.Mode().Perm())
+		}
+	}
+}
+
+// TestTokenCacheExpiryAndDelete tests that expired and deleted tokens are not returned
+func TestTokenCacheExpiryAndDelete(t *testing.T) {
+	cache := NewTokenCache(filepath.Join(t.TempDir(), "tokens.json"))
+
+	if err := cache.Put("expired", "old-token", time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("failed to put token: %v", err)
+	}
+	if _, _, ok := cache.Get("expired"); ok {
+		t.Error("expected expired token to be ignored")
+	}
+
+	if err := cache.Put("valid", "token", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("failed to put token: %v", err)
+	}
+	if err := cache.Delete("valid"); err != nil {
+		t.Fatalf("failed to delete token: %v", err)
+	}
+	if _, _, ok := cache.Get("valid"); ok {
+		t.Error("expected deleted token to be gone")
+	}
+}
+
+// TestTokenCacheKey tests that keys do not contain the consumer key in clear
+func TestTokenCacheKey(t *testing.T) {
+	key := TokenCacheKey("sandbox", "my-consumer-key")
+	if key == TokenCacheKey("sandbox", "other-key") {
+		t.Error("expected different keys for different consumer keys")
+	}
+	if !strings.HasPrefix(key, "sandbox:") || strings.Contains(key, "my-consumer-key") {
+		t.Errorf("unexpected cache key format: %s", key)
+	}
+}
+
+// TestTokenCacheDeleteConsumer tests that every token of a consumer key is listed and removed
+func TestTokenCacheDeleteConsumer(t *testing.T) {
+	cache := NewTokenCache(filepath.Join(t.TempDir(), "tokens.json"))
+	expiresAt := time.Now().Add(time.Hour).Round(time.Second)
+
+	for _, environment := range []string{"sandbox", "production", "http://127.0.0.1:8080"} {
+		if err := cache.Put(TokenCacheKey(environment, "key"), "token", expiresAt); err != nil {
+			t.Fatalf("failed to put token: %v", err)
+		}
+	}
+	if err := cache.Put(TokenCacheKey("sandbox", "other-key"), "other-token", expiresAt); err != nil {
+		t.Fatalf("failed to put token: %v", err)
+	}
+
+	expiries := cache.Expiries("key")
+	if len(expiries) != 3 || !expiries["http://127.0.0.1:8080"].Equal(expiresAt) {
+		t.Errorf("expected 3 tokens including the base URL one, got %v", expiries)
+	}
+
+	if err := cache.DeleteConsumer("key"); err != nil {
+		t.Fatalf("failed to delete tokens: %v", err)
+	}
+	if expiries := cache.Expiries("key"); len(expiries) != 0 {
+		t.Errorf("expected all tokens of the key to be gone, got %v", expiries)
+	}
+	if _, _, ok := cache.Get(TokenCacheKey("sandbox", "other-key")); !ok {
+		t.Error("expected tokens of other keys to be kept")
+	}
+}
@@ -1,14 +1,43 @@
 package mpesa
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"time"
 )
 
+// Defaults of Transaction Status queries
+const (
+	defaultStatusRemarks  = "Status Check"
+	defaultStatusOccasion = "Verification"
+
+	// maxOccasionLength is the occasion limit of the initiator-based APIs
+	maxOccasionLength = 100
+)
+
+// TransactionStatusOptions describes a Transaction Status query. Either TransactionID
+// or OriginalConversationID identifies the transaction.
+type TransactionStatusOptions struct {
+	// TransactionID is the M-Pesa receipt number of the transaction
+	TransactionID string
+
+	// OriginalConversationID is the OriginatorConversationID of the request that
+	// made the transaction, for transactions whose receipt number is not known
+	OriginalConversationID string
+
+	// IdentifierType is the type of PartyA: IdentifierMSISDN, IdentifierTill or
+	// IdentifierShortcode (the default)
+	IdentifierType string
+
+	// PartyA is the party that made or received the transaction; it defaults to the
+	// business shortcode and must be given for IdentifierMSISDN
+	PartyA string
+
+	// Remarks are additional information for the query (2 to 100 characters)
+	Remarks string
+
+	// Occasion is any additional information to be associated with the query (up to 100 characters)
+	Occasion string
+}
+
 // transactionStatusRequest represents the JSON payload sent to the M-Pesa Transaction Status API.
 // This struct contains all the required fields for querying the status of a transaction.
 type transactionStatusRequest struct {
@@ -24,6 +53,9 @@ type transactionStatusRequest struct {
 	// TransactionID is the unique identifier of the transaction being queried
 	TransactionID string `json:"TransactionID"`
 
+	// OriginalConversationID identifies the transaction by the originator conversation ID of its request
+	OriginalConversationID string `json:"OriginalConversationID,omitempty"`
+
 	// PartyA is the organization's shortcode (Paybill or Buygoods)
 	PartyA string `json:"PartyA"`
 
@@ -78,62 +110,90 @@ func QueryTransactionWithConfig(accessToken, transactionID string, config *Confi
 		}
 	}
 
-	reqBody := transactionStatusRequest{
-		Initiator:          config.Initiator,
-		SecurityCredential: config.SecurityCredential,
-		CommandID:          "TransactionStatusQuery",
-		TransactionID:      transactionID,
-		PartyA:             config.BusinessShortcode,
-		IdentifierType:     "4",
-		ResultURL:          config.ResultURL,
-		QueueTimeOutURL:    config.QueueTimeOutURL,
-		Remarks:            "Status Check",
-		Occasion:           "Verification",
-	}
+	return NewClient(config, WithTokenSource(StaticToken(accessToken))).QueryTransaction(transactionID)
+}
+
+// QueryTransaction queries the status of a specific M-Pesa transaction using the
+// shortcode, initiator and callback URLs from the client's config.
+func (c *Client) QueryTransaction(transactionID string) (*transactionStatusResponse, error) {
+	return c.QueryTransactionStatus(TransactionStatusOptions{TransactionID: transactionID})
+}
 
-	// Convert the request body struct to a JSON byte slice
-	jsonBody, err := json.Marshal(reqBody)
+// QueryTransactionStatus queries the status of a transaction described by opts, using
+// the initiator and callback URLs from the client's config. The options are validated
+// before anything is sent. The details of the transaction are delivered to the ResultURL.
+func (c *Client) QueryTransactionStatus(opts TransactionStatusOptions) (*transactionStatusResponse, error) {
+	reqBody, err := c.newTransactionStatusRequest(opts)
 	if err != nil {
-		return nil, fmt.Errorf("error marshalling request body: %w", err)
+		return nil, err
 	}
 
-	// Use appropriate URL based on environment
-	var url string
-	if config.Environment == "production" {
-		url = "https://api.safaricom.co.ke/mpesa/transactionstatus/v1/query"
-	} else {
-		url = "https://sandbox.safaricom.co.ke/mpesa/transactionstatus/v1/query"
+	var result transactionStatusResponse
+	if err := c.postJSON(transactionStatusPath, reqBody, &result); err != nil {
+		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
+	return &result, nil
+}
 
-	// Set the required headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+accessToken)
+// newTransactionStatusRequest validates opts and fills in the defaults of a Transaction Status query.
+func (c *Client) newTransactionStatusRequest(opts TransactionStatusOptions) (*transactionStatusRequest, error) {
+	if opts.TransactionID == "" && opts.OriginalConversationID == "" {
+		return nil, fmt.Errorf("a transaction ID or original conversation ID is required")
+	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error sending request: %w", err)
+	identifierType := opts.IdentifierType
+	if identifierType == "" {
+		identifierType = IdentifierShortcode
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
+	partyA := opts.PartyA
+	switch identifierType {
+	case IdentifierMSISDN:
+		if partyA == "" {
+			return nil, fmt.Errorf("party A is required when identifying it by phone number")
+		}
+		phone, err := NormalizePhoneNumber(partyA)
+		if err != nil {
+			return nil, err
+		}
+		partyA = phone
+	case IdentifierTill, IdentifierShortcode:
+		if partyA == "" {
+			partyA = c.config.BusinessShortcode
+		}
+	default:
+		return nil, fmt.Errorf("identifier type must be %s (MSISDN), %s (till) or %s (shortcode), got: %s",
+			IdentifierMSISDN, IdentifierTill, IdentifierShortcode, identifierType)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("api request failed with status %d: %s", resp.StatusCode, string(respBody))
+	remarks := opts.Remarks
+	if remarks == "" {
+		remarks = defaultStatusRemarks
+	}
+	if len(remarks) < minRemarksLength || len(remarks) > maxRemarksLength {
+		return nil, fmt.Errorf("remarks must be %d to %d characters", minRemarksLength, maxRemarksLength)
 	}
 
-	var result transactionStatusResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse transaction status response: %w", err)
+	occasion := opts.Occasion
+	if occasion == "" {
+		occasion = defaultStatusOccasion
+	}
+	if len(occasion) > maxOccasionLength {
+		return nil, fmt.Errorf("occasion must be at most %d characters", maxOccasionLength)
 	}
 
-	return &result, nil
+	return &transactionStatusRequest{
+		Initiator:              c.config.Initiator,
+		SecurityCredential:     c.config.SecurityCredential,
+		CommandID:              "TransactionStatusQuery",
+		TransactionID:          opts.TransactionID,
+		OriginalConversationID: opts.OriginalConversationID,
+		PartyA:                 partyA,
+		IdentifierType:         identifierType,
+		ResultURL:              c.config.ResultURL,
+		QueueTimeOutURL:        c.config.QueueTimeOutURL,
+		Remarks:                remarks,
+		Occasion:               occasion,
+	}, nil
 }
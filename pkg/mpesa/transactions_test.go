@@ -2,6 +2,7 @@ package mpesa
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -27,22 +28,32 @@ func TestQueryTransactionWithConfigSuccess(t *testing.T) {
 		ResponseDescription:      "The service request is processed successfully.",
 	}
 
+	var received transactionStatusRequest
+
 	// Create test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Verify request method and headers
+		// Verify request method, path and headers
 		if r.Method != "POST" {
 			t.Errorf("expected POST request, got %s", r.Method)
 		}
 
+		if r.URL.Path != "/mpesa/transactionstatus/v1/query" {
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+
 		if r.Header.Get(contentType) != applicationJSON {
 			t.Errorf("expected Content-Type application/json, got %s", r.Header.Get(contentType))
 		}
 
 		authHeader := r.Header.Get("Authorization")
-		if !strings.HasPrefix(authHeader, "Bearer ") {
+		if authHeader != "Bearer "+testAccessToken {
 			t.Errorf("expected Bearer token in Authorization header, got %s", authHeader)
 		}
 
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+
 		// Send mock response
 		w.Header().Set(contentType, applicationJSON)
 		_ = json.NewEncoder(w).Encode(mockResponse)
@@ -59,16 +70,104 @@ func TestQueryTransactionWithConfigSuccess(t *testing.T) {
 		QueueTimeOutURL:    testTimeoutURL,
 	}
 
-	accessToken := testAccessToken
-	transactionID := "TEST12345"
+	client := NewClient(testConfig, WithBaseURL(server.URL), WithTokenSource(StaticToken(testAccessToken)))
 
-	// Test with config but it will still try to connect to real endpoint
-	// This is a limitation of the current implementation
-	_, err := QueryTransactionWithConfig(accessToken, transactionID, testConfig)
+	result, err := client.QueryTransaction("TEST12345")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
 
-	// Since we can't override the URL easily, expect an error (network failure)
-	if err == nil {
-		t.Error("expected error when connecting to real URL, got nil")
+	if *result != mockResponse {
+		t.Errorf("expected response %+v, got %+v", mockResponse, *result)
+	}
+
+	if received.TransactionID != "TEST12345" {
+		t.Errorf("expected TransactionID 'TEST12345', got '%s'", received.TransactionID)
+	}
+
+	if received.PartyA != "600986" || received.SecurityCredential != testCredential {
+		t.Errorf("request did not use config values: %+v", received)
+	}
+
+	if received.ResultURL != testResultURL || received.QueueTimeOutURL != testTimeoutURL {
+		t.Errorf("request did not use config callback URLs: %+v", received)
+	}
+}
+
+// TestQueryTransactionAPIError tests that non-200 responses are reported as APIError
+func TestQueryTransactionAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"requestId":"123","errorCode":"400.002.02","errorMessage":"Bad Request - Invalid TransactionID"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(GetDefaultConfig(), WithBaseURL(server.URL), WithTokenSource(StaticToken("token")))
+
+	_, err := client.QueryTransaction("BAD")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+
+	if apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", apiErr.StatusCode)
+	}
+
+	if apiErr.ErrorCode != "400.002.02" {
+		t.Errorf("expected error code '400.002.02', got '%s'", apiErr.ErrorCode)
+	}
+
+	if !strings.Contains(err.Error(), "api request failed with status 400") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
+
+// TestTransactionStatusOptions tests defaults and validation of Transaction Status queries
+func TestTransactionStatusOptions(t *testing.T) {
+	client := NewClient(GetDefaultConfig(), WithTokenSource(StaticToken("token")))
+
+	req, err := client.newTransactionStatusRequest(TransactionStatusOptions{TransactionID: "OEI2AK4Q16"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if req.IdentifierType != IdentifierShortcode || req.PartyA != "600986" || req.Remarks != "Status Check" || req.Occasion != "Verification" {
+		t.Errorf("expected defaults, got %+v", req)
+	}
+
+	req, err = client.newTransactionStatusRequest(TransactionStatusOptions{
+		OriginalConversationID: "AG_20240706_2010",
+		IdentifierType:         IdentifierMSISDN,
+		PartyA:                 "0708374149",
+		Remarks:                "Missing receipt",
+		Occasion:               "Backfill",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if req.PartyA != "254708374149" || req.OriginalConversationID != "AG_20240706_2010" || req.TransactionID != "" {
+		t.Errorf("unexpected request %+v", req)
+	}
+
+	tests := []struct {
+		name      string
+		opts      TransactionStatusOptions
+		errorText string
+	}{
+		{name: "no identifier", opts: TransactionStatusOptions{}, errorText: "transaction ID or original conversation ID"},
+		{name: "unknown identifier type", opts: TransactionStatusOptions{TransactionID: "X", IdentifierType: "3"}, errorText: "identifier type"},
+		{name: "msisdn without party A", opts: TransactionStatusOptions{TransactionID: "X", IdentifierType: IdentifierMSISDN}, errorText: "party A"},
+		{name: "short remarks", opts: TransactionStatusOptions{TransactionID: "X", Remarks: "a"}, errorText: "remarks"},
+		{name: "long occasion", opts: TransactionStatusOptions{TransactionID: "X", Occasion: strings.Repeat("a", 101)}, errorText: "occasion"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.newTransactionStatusRequest(tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.errorText) {
+				t.Errorf("expected error containing %q, got %v", tt.errorText, err)
+			}
+		})
 	}
 }
 
@@ -0,0 +1,109 @@
+package mpesa
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Verification records when the credentials of a profile were last accepted by M-Pesa.
+type Verification struct {
+	// Environment is the environment the credentials were verified against
+	Environment string `json:"environment"`
+
+	// VerifiedAt is when M-Pesa last issued an access token for the credentials
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+// VerificationStore keeps track of when the credentials of each profile were last verified.
+// Only the time and environment are recorded, never the credentials themselves.
+type VerificationStore struct {
+	path string
+}
+
+// NewVerificationStore returns a verification store backed by the file at path.
+func NewVerificationStore(path string) *VerificationStore {
+	return &VerificationStore{path: path}
+}
+
+// DefaultVerificationsPath returns the location of the verification store in the user's config directory.
+func DefaultVerificationsPath() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "verifications.json"), nil
+}
+
+// Record stores v as the latest verification of profile.
+func (s *VerificationStore) Record(profile string, v Verification) error {
+	verifications, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	verifications[profile] = v
+	return s.save(verifications)
+}
+
+// Get returns the latest verification of profile, or nil if there is none.
+func (s *VerificationStore) Get(profile string) (*Verification, error) {
+	verifications, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	v, ok := verifications[profile]
+	if !ok {
+		return nil, nil
+	}
+	return &v, nil
+}
+
+// Delete forgets the verification of profile.
+func (s *VerificationStore) Delete(profile string) error {
+	verifications, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := verifications[profile]; !ok {
+		return nil
+	}
+
+	delete(verifications, profile)
+	return s.save(verifications)
+}
+
+// load reads all verifications. A missing file is not an error.
+func (s *VerificationStore) load() (map[string]Verification, error) {
+	verifications := map[string]Verification{}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return verifications, nil
+		}
+		return nil, fmt.Errorf("failed to read verifications: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &verifications); err != nil {
+		return nil, fmt.Errorf("failed to parse verifications: %w", err)
+	}
+
+	return verifications, nil
+}
+
+// save atomically replaces the verification file.
+func (s *VerificationStore) save(verifications map[string]Verification) error {
+	data, err := json.MarshalIndent(verifications, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode verifications: %w", err)
+	}
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("failed to write verifications: %w", err)
+	}
+	return nil
+}
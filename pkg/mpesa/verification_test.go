@@ -0,0 +1,36 @@
+package mpesa
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestVerificationStore tests recording, reading and deleting verifications across store instances
+func TestVerificationStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mpesa-cli", "verifications.json")
+	verifiedAt := time.Now().Round(time.Second)
+
+	if v, err := NewVerificationStore(path).Get("default"); err != nil || v != nil {
+		t.Fatalf("expected no verification before any is recorded, got %v %v", v, err)
+	}
+
+	if err := NewVerificationStore(path).Record("default", Verification{Environment: "sandbox", VerifiedAt: verifiedAt}); err != nil {
+		t.Fatalf("failed to record verification: %v", err)
+	}
+
+	v, err := NewVerificationStore(path).Get("default")
+	if err != nil || v == nil {
+		t.Fatalf("expected verification, got %v %v", v, err)
+	}
+	if v.Environment != "sandbox" || !v.VerifiedAt.Equal(verifiedAt) {
+		t.Errorf("expected sandbox at %v, got %s at %v", verifiedAt, v.Environment, v.VerifiedAt)
+	}
+
+	if err := NewVerificationStore(path).Delete("default"); err != nil {
+		t.Fatalf("failed to delete verification: %v", err)
+	}
+	if v, err := NewVerificationStore(path).Get("default"); err != nil || v != nil {
+		t.Errorf("expected verification to be deleted, got %v %v", v, err)
+	}
+}